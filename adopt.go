@@ -0,0 +1,51 @@
+package pvci
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// adoptInjectorJob resolves an injector Job create that found a Job of
+// the same name, typically left by a PVCI replica that stopped before
+// cleaning up. A running PVCI managed injector of the volume is adopted
+// and waited on like a new one; a finished one is replaced by job.
+// Jobs not managed by PVCI for the volume are a conflict.
+func (a *API) adoptInjectorJob(job *batchV1.Job, volName string) error {
+	ctx := context.Background()
+	jobsClient := a.Cs.BatchV1().Jobs(job.Namespace)
+
+	existing, err := jobsClient.Get(ctx, job.Name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if existing.Labels[a.labelKey("vol")] != volName || existing.Labels[a.labelKey("job")] != "injector" {
+		return conflict("found Job %s not managed by PVCI for %s", job.Name, volName)
+	}
+
+	if _, finished := jobFinished(*existing); !finished {
+		a.Log.Info("adopting running injector Job",
+			zap.String("namespace", job.Namespace),
+			zap.String("name", job.Name),
+		)
+		return nil
+	}
+
+	a.Log.Info("replacing finished injector Job",
+		zap.String("namespace", job.Namespace),
+		zap.String("name", job.Name),
+	)
+
+	propagation := metaV1.DeletePropagationBackground
+	err = jobsClient.Delete(ctx, job.Name, metaV1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		return err
+	}
+
+	_, err = jobsClient.Create(ctx, job, metaV1.CreateOptions{})
+
+	return err
+}
@@ -0,0 +1,175 @@
+package pvci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// autoGrowAnnotation opts a PVC into AutoGrow when "true".
+const autoGrowAnnotation = "pvci.txn2.com/autogrow"
+
+// Defaults of the AutoGrow thresholds.
+const (
+	DefaultAutoGrowThreshold = 80
+	DefaultAutoGrowPercent   = 50
+)
+
+// EventAutoGrown is recorded on a PVC expanded by AutoGrow.
+const EventAutoGrown = "AutoGrown"
+
+// autoGrowSize returns the storage request a volume using used of
+// capacity bytes grows to, when its usage reached threshold percent.
+func autoGrowSize(request resource.Quantity, used int64, capacity int64, threshold int, growPercent int) (resource.Quantity, bool) {
+	if capacity <= 0 || used*100 < capacity*int64(threshold) {
+		return request, false
+	}
+
+	grown := resource.Quantity{}
+	grown.Set(request.Value() + request.Value()*int64(growPercent)/100)
+
+	return grown, true
+}
+
+// AutoGrow expands the bound PVCI managed PVCs annotated
+// pvci.txn2.com/autogrow whose usage reached AutoGrowThreshold percent,
+// growing their request by AutoGrowPercent. Usage is read from the
+// kubelet stats summary of nodes mounting the PVC, so idle PVCs are
+// never grown. PVCs of storage classes without volume expansion are
+// skipped. The PVCs expanded are returned.
+func (a *API) AutoGrow() ([]ReconcileAction, error) {
+	ctx := context.Background()
+
+	selector := fmt.Sprintf("%s=%s", a.labelKey("service"), a.Service)
+
+	grown := make([]ReconcileAction, 0)
+	for _, ns := range a.managedNamespaces() {
+		pvcs, err := a.Cs.CoreV1().PersistentVolumeClaims(ns).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return grown, err
+		}
+
+		for i := range pvcs.Items {
+			pvc := &pvcs.Items[i]
+			if pvc.Annotations[autoGrowAnnotation] != "true" || pvc.Status.Phase != coreV1.ClaimBound {
+				continue
+			}
+
+			if pvc.Spec.StorageClassName == nil || !a.allowsExpansion(*pvc.Spec.StorageClassName) {
+				continue
+			}
+
+			used, capacity, err := a.pvcUsage(ctx, pvc)
+			if err != nil {
+				a.Log.Warn("unable to get PVC usage",
+					zap.String("namespace", pvc.Namespace),
+					zap.String("name", pvc.Name),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			request := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
+			qty, ok := autoGrowSize(request, used, capacity, a.AutoGrowThreshold, a.AutoGrowPercent)
+			if !ok {
+				continue
+			}
+
+			patchJson, _ := json.Marshal(map[string]interface{}{
+				"spec": map[string]interface{}{
+					"resources": map[string]interface{}{
+						"requests": map[string]string{
+							string(coreV1.ResourceStorage): qty.String(),
+						},
+					},
+				},
+			})
+
+			_, err = a.Cs.CoreV1().PersistentVolumeClaims(ns).Patch(ctx, pvc.Name, types.MergePatchType, patchJson, metaV1.PatchOptions{})
+			if err != nil {
+				return grown, err
+			}
+
+			reason := fmt.Sprintf("%d of %d bytes used, grown from %s to %s", used, capacity, request.String(), qty.String())
+			a.event(pvc, EventAutoGrown, "%s", reason)
+
+			grown = append(grown, ReconcileAction{
+				Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name,
+				Reason: reason,
+			})
+		}
+	}
+
+	return grown, nil
+}
+
+// pvcUsage returns the used and capacity bytes of a PVC mounted by a
+// running pod, from the kubelet stats summary of the pod's node.
+func (a *API) pvcUsage(ctx context.Context, pvc *coreV1.PersistentVolumeClaim) (int64, int64, error) {
+	pods, err := a.Cs.CoreV1().Pods(pvc.Namespace).List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase != coreV1.PodRunning || !mountsPVC(pod, pvc.Name) {
+			continue
+		}
+
+		summary, err := a.nodeStatsSummary(ctx, pod.Spec.NodeName)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, ps := range summary.Pods {
+			for _, v := range ps.Volume {
+				if v.PVCRef != nil && v.PVCRef.Name == pvc.Name && v.PVCRef.Namespace == pvc.Namespace {
+					return v.UsedBytes, v.CapacityBytes, nil
+				}
+			}
+		}
+	}
+
+	return 0, 0, fmt.Errorf("PVC %s is not mounted by a running pod", pvc.Name)
+}
+
+// mountsPVC reports whether a pod mounts a PVC.
+func mountsPVC(pod coreV1.Pod, claimName string) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName == claimName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AutoGrowLoop runs AutoGrow every interval, logging the PVCs grown.
+// Runs are skipped in read-only mode.
+func (a *API) AutoGrowLoop(interval time.Duration) {
+	for range time.Tick(interval) {
+		if a.readOnly.get() {
+			continue
+		}
+
+		grown, err := a.AutoGrow()
+		if err != nil {
+			a.Log.Error("autogrow failed", zap.Error(err))
+		}
+
+		for _, action := range grown {
+			a.Log.Info("grew PVC",
+				zap.String("namespace", action.Namespace),
+				zap.String("name", action.Name),
+				zap.String("reason", action.Reason),
+			)
+		}
+	}
+}
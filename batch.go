@@ -0,0 +1,151 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StatusBatchRequest structures the body of the /status-batch endpoint.
+// Either a list of Volumes or a Namespace with a LabelSelector matching
+// PVCs may be given.
+type StatusBatchRequest struct {
+	Volumes       []VolConfig `json:"volumes"`
+	Namespace     string      `json:"namespace"`
+	LabelSelector string      `json:"label_selector"`
+}
+
+// VolStatusReport is a StatusReport for a single volume
+// returned by GetStatusBatch.
+type VolStatusReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	StatusReport
+}
+
+// GetStatusBatchHandler is used by the HTTP POST /status-batch endpoint
+// and returns a list of VolStatusReport objects as JSON.
+func (a *API) GetStatusBatchHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		statusBatchRequest := &StatusBatchRequest{}
+		err := a.parseBody(c, statusBatchRequest)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		reports, err := a.GetStatusBatch(*statusBatchRequest)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, reports)
+	}
+}
+
+// GetStatusBatch returns status reports for many volumes. Injector pods
+// and PVCs are fetched with a single list call for each namespace
+// rather than per volume.
+func (a *API) GetStatusBatch(statusBatchRequest StatusBatchRequest) ([]VolStatusReport, error) {
+	ctx := context.Background()
+	reports := make([]VolStatusReport, 0)
+
+	volumes := statusBatchRequest.Volumes
+
+	// resolve volumes from PVCs matching a label selector
+	if statusBatchRequest.LabelSelector != "" {
+		pvcRequestConfig := PVCRequestConfig{VolConfig: VolConfig{Namespace: statusBatchRequest.Namespace}}
+		err := a.resolveNamespace(&pvcRequestConfig)
+		if err != nil {
+			return reports, err
+		}
+
+		pvcs, err := a.Cs.CoreV1().PersistentVolumeClaims(pvcRequestConfig.Namespace).List(ctx, metaV1.ListOptions{
+			LabelSelector: statusBatchRequest.LabelSelector,
+		})
+		if err != nil {
+			return reports, err
+		}
+
+		// source PVCs carry the volume label of the PVC they populate
+		seen := make(map[string]bool)
+		for _, pvc := range pvcs.Items {
+			name := pvc.Name
+			if vol, ok := pvc.Labels[a.labelKey("vol")]; ok {
+				name = vol
+			}
+
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			volumes = append(volumes, VolConfig{Namespace: pvc.Namespace, Name: name})
+		}
+	}
+
+	// group volumes by resolved namespace
+	byNamespace := make(map[string][]VolConfig)
+	namespaces := make([]string, 0)
+	for _, vol := range volumes {
+		pvcRequestConfig := PVCRequestConfig{VolConfig: vol}
+		err := a.resolveNamespace(&pvcRequestConfig)
+		if err != nil {
+			return reports, err
+		}
+
+		if _, ok := byNamespace[pvcRequestConfig.Namespace]; !ok {
+			namespaces = append(namespaces, pvcRequestConfig.Namespace)
+		}
+		byNamespace[pvcRequestConfig.Namespace] = append(byNamespace[pvcRequestConfig.Namespace], pvcRequestConfig.VolConfig)
+	}
+
+	for _, ns := range namespaces {
+		pods, podsErr := a.Cs.CoreV1().Pods(ns).List(ctx, metaV1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=injector", a.labelKey("job")),
+		})
+
+		podsByVol := make(map[string][]coreV1.Pod)
+		if podsErr == nil {
+			for _, pod := range pods.Items {
+				vol := pod.Labels[a.labelKey("vol")]
+				podsByVol[vol] = append(podsByVol[vol], pod)
+			}
+		}
+
+		pvcs, pvcsErr := a.Cs.CoreV1().PersistentVolumeClaims(ns).List(ctx, metaV1.ListOptions{})
+
+		pvcsByName := make(map[string]*coreV1.PersistentVolumeClaim)
+		if pvcsErr == nil {
+			for i := range pvcs.Items {
+				pvcsByName[pvcs.Items[i].Name] = &pvcs.Items[i]
+			}
+		}
+
+		for _, vol := range byNamespace[ns] {
+			report := VolStatusReport{Namespace: ns, Name: vol.Name}
+
+			report.setInjectorStatus(podsByVol[vol.Name], podsErr)
+
+			pvc, ok := pvcsByName[vol.Name]
+			switch {
+			case pvcsErr != nil:
+				report.setPVCStatus(nil, pvcsErr)
+			case !ok:
+				report.setPVCStatus(nil, fmt.Errorf("persistentvolumeclaims \"%s\" not found", vol.Name))
+			default:
+				report.setPVCStatus(pvc, nil)
+			}
+
+			reports = append(reports, report)
+		}
+	}
+
+	return reports, nil
+}
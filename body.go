@@ -0,0 +1,49 @@
+package pvci
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodySize is the request body limit when
+// Config.MaxBodySize is not set.
+const DefaultMaxBodySize = 1 << 20
+
+// tooLarge returns an Error for request bodies exceeding MaxBodySize.
+func tooLarge(format string, args ...interface{}) error {
+	return newError(ErrCodeTooLarge, http.StatusRequestEntityTooLarge, format, args...)
+}
+
+// parseBody reads at most MaxBodySize bytes of a JSON request body
+// into v, distinguishing oversized, empty and malformed bodies.
+func (a *API) parseBody(c *gin.Context, v interface{}) error {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, a.MaxBodySize)
+
+	rs, err := ioutil.ReadAll(body)
+	if err != nil {
+		if err.Error() == "http: request body too large" {
+			return tooLarge("request body exceeds %d bytes", a.MaxBodySize)
+		}
+		return err
+	}
+
+	if len(rs) == 0 {
+		return badRequest("request body is empty")
+	}
+
+	err = json.Unmarshal(rs, v)
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return badRequest("malformed JSON: %s", err.Error())
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package pvci
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Circuit breaker states of a K8sBreaker.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// DefaultK8sBreakerCooldown is how long a K8sBreaker stays open
+// before probing the Kubernetes API again when no cooldown is set.
+const DefaultK8sBreakerCooldown = 30 * time.Second
+
+// K8sBreaker is a circuit breaker around the Kubernetes API, wrapping
+// the transport of the clientset. After Threshold consecutive failed
+// requests (transport errors, 429 or 5xx responses) it opens, failing
+// requests with K8S_UNAVAILABLE without sending them. Once Cooldown
+// has passed it half-opens, letting a single request probe the API:
+// a response closes the breaker, a failure opens it again.
+type K8sBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+	Log       *zap.Logger
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+// NewK8sBreaker returns a closed K8sBreaker. A threshold of zero or
+// less disables it.
+func NewK8sBreaker(threshold int, cooldown time.Duration, log *zap.Logger) *K8sBreaker {
+	if cooldown <= 0 {
+		cooldown = DefaultK8sBreakerCooldown
+	}
+
+	return &K8sBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		Log:       log,
+		state:     BreakerClosed,
+	}
+}
+
+// State returns the breaker state. An open breaker half-opens on the
+// first request past its cooldown.
+func (b *K8sBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// retryAfter returns the remaining cooldown of an open breaker.
+func (b *K8sBreaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return 0
+	}
+
+	return b.Cooldown - time.Since(b.openedAt)
+}
+
+// allow reports whether a request may be sent, half-opening an open
+// breaker past its cooldown for the request to probe the API. Rejected
+// requests get the time to wait before retrying.
+func (b *K8sBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		wait := b.Cooldown - time.Since(b.openedAt)
+		if wait > 0 {
+			return wait, false
+		}
+
+		b.state = BreakerHalfOpen
+		b.Log.Info("kubernetes API circuit breaker half-open, probing")
+
+		return 0, true
+	case BreakerHalfOpen:
+		// a probe is in flight
+		return b.Cooldown, false
+	}
+
+	return 0, true
+}
+
+// record updates the breaker with the outcome of a request.
+func (b *K8sBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if b.state != BreakerClosed {
+			b.Log.Info("kubernetes API circuit breaker closed")
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures += 1
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.Threshold) {
+		b.Log.Warn("kubernetes API circuit breaker open",
+			zap.Int("failures", b.failures),
+			zap.Duration("cooldown", b.Cooldown),
+		)
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon returns a half-open breaker whose probe was canceled to
+// open, letting the next request probe the API instead.
+func (b *K8sBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+	}
+}
+
+// Wrap wraps the transport of a Kubernetes client with the breaker,
+// for rest.Config.Wrap.
+func (b *K8sBreaker) Wrap(rt http.RoundTripper) http.RoundTripper {
+	if b.Threshold <= 0 {
+		return rt
+	}
+
+	return &breakerTransport{breaker: b, rt: rt}
+}
+
+// breakerTransport fails requests while its breaker is open.
+type breakerTransport struct {
+	breaker *K8sBreaker
+	rt      http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait, ok := t.breaker.allow()
+	if !ok {
+		return nil, k8sUnavailable(wait)
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+
+	// requests canceled by the caller say nothing of the API
+	if err != nil && req.Context().Err() != nil {
+		t.breaker.abandon()
+		return resp, err
+	}
+
+	t.breaker.record(err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+
+	return resp, err
+}
+
+// k8sUnavailable returns an Error for requests rejected by an open
+// K8sBreaker, to be retried after wait.
+func k8sUnavailable(wait time.Duration) error {
+	return &Error{
+		Code:       ErrCodeK8sUnavailable,
+		Status:     http.StatusServiceUnavailable,
+		Err:        fmt.Errorf("kubernetes API circuit breaker is open, retry after %s", wait.Round(time.Second)),
+		RetryAfter: wait,
+	}
+}
+
+// retryAfterHeader formats a wait as the seconds of a Retry-After
+// header, rounded up.
+func retryAfterHeader(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
@@ -0,0 +1,138 @@
+package pvci
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CallbackSignatureHeader carries the hex encoded HMAC-SHA256 of a
+// callback body, keyed by Config.CallbackSecret.
+const CallbackSignatureHeader = "X-PVCI-Signature"
+
+// CallbackPayload is POSTed as JSON to the callback_url of a request
+// when an operation on its volume completes.
+type CallbackPayload struct {
+	Operation string    `json:"operation"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// notify sends a CallbackPayload for an operation to the request's
+// callback URL, if one was given, and to the configured Publisher.
+// Delivery is asynchronous and failures are logged.
+func (a *API) notify(operation string, pvcRequestConfig PVCRequestConfig, opErr error) {
+	payload := CallbackPayload{
+		Operation: operation,
+		Namespace: pvcRequestConfig.Namespace,
+		Name:      pvcRequestConfig.Name,
+		Success:   opErr == nil,
+		Time:      time.Now().UTC(),
+	}
+
+	if opErr != nil {
+		payload.Error = opErr.Error()
+	}
+
+	a.publish(payload)
+
+	if pvcRequestConfig.CallbackURL == "" {
+		return
+	}
+
+	go func() {
+		err := a.sendCallback(pvcRequestConfig.CallbackURL, payload)
+		if err != nil {
+			a.Log.Warn("callback failed",
+				zap.String("operation", operation),
+				zap.String("namespace", payload.Namespace),
+				zap.String("name", payload.Name),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// checkCallbackURL validates the callback URL of a request as an http
+// or https URL naming one of Config.CallbackAllowedHosts, keeping
+// requests from directing PVCI at internal services.
+func (a *API) checkCallbackURL(callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return badRequest("callback_url must be an http or https URL")
+	}
+
+	if len(a.CallbackAllowedHosts) == 0 {
+		return forbidden("callbacks are disabled, no callback hosts are allowed")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range a.CallbackAllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return nil
+		}
+	}
+
+	return forbidden("callback_url host %s is not allowed", u.Hostname())
+}
+
+// sendCallback POSTs a signed CallbackPayload to an allowed callback
+// URL, without following redirects elsewhere.
+func (a *API) sendCallback(callbackURL string, payload CallbackPayload) error {
+	err := a.checkCallbackURL(callbackURL)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if a.CallbackSecret != "" {
+		mac := hmac.New(sha256.New, []byte(a.CallbackSecret))
+		mac.Write(body)
+		req.Header.Set(CallbackSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package pvci
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Capabilities are the volume features of the cluster detected when
+// the API starts. PVC clones are provisioned by CSI drivers only, with
+// no VolumeSnapshot API involved, so CSIDrivers lists the provisioners
+// able to clone. Error is set when the probe failed and the
+// capabilities are unknown.
+type Capabilities struct {
+	CSIDrivers []string  `json:"csi_drivers"`
+	Probed     time.Time `json:"probed"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// probeCapabilities detects the CSI drivers of the cluster.
+func (a *API) probeCapabilities() Capabilities {
+	capabilities := Capabilities{CSIDrivers: make([]string, 0), Probed: time.Now().UTC()}
+
+	drivers, err := a.Cs.StorageV1().CSIDrivers().List(context.Background(), metaV1.ListOptions{})
+	if err != nil {
+		capabilities.Error = err.Error()
+		a.Log.Warn("unable to probe CSI drivers, clone support is unknown", zap.Error(err))
+		return capabilities
+	}
+
+	for _, driver := range drivers.Items {
+		capabilities.CSIDrivers = append(capabilities.CSIDrivers, driver.Name)
+	}
+
+	if len(capabilities.CSIDrivers) == 0 {
+		a.Log.Warn("no CSI drivers found, creates can not clone their source PVC")
+	}
+
+	return capabilities
+}
+
+// checkCloneSupport validates that the storage class of a VolConfig is
+// provisioned by a CSI driver able to clone the source PVC, rather than
+// leaving the final PVC pending. Checks are skipped unless
+// Config.RequireCloneSupport is set and the capabilities are known.
+func (a *API) checkCloneSupport(volConfig VolConfig) error {
+	if !a.RequireCloneSupport || a.capabilities.Error != "" {
+		return nil
+	}
+
+	// the final PVC is the clone
+	finalStorageClass := volConfig.finalStorageClass()
+
+	reason, err := a.cloneUnsupported(finalStorageClass)
+	if apiErrors.IsNotFound(err) {
+		return storageClassNotFound(finalStorageClass)
+	}
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		return cloneIncompatibleError(volConfig.Name, finalStorageClass, reason)
+	}
+
+	return nil
+}
+
+// cloneUnsupported returns why a storage class can not clone volumes,
+// or an empty reason when its provisioner is a detected CSI driver or
+// the capabilities are unknown.
+func (a *API) cloneUnsupported(storageClass string) (string, error) {
+	if a.capabilities.Error != "" {
+		return "", nil
+	}
+
+	sc, err := a.getStorageClass(storageClass)
+	if err != nil {
+		return "", err
+	}
+
+	for _, driver := range a.capabilities.CSIDrivers {
+		if driver == sc.Provisioner {
+			return "", nil
+		}
+	}
+
+	return "provisioner " + sc.Provisioner + " is not a CSI driver and can not clone volumes", nil
+}
+
+// ConfigHandler used by the HTTP GET /config endpoint reports the
+// non-secret configuration of the API and the detected Capabilities.
+func (a *API) ConfigHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"service":                 a.Service,
+			"version":                 a.Version,
+			"default_namespace":       a.DefaultNamespace,
+			"allowed_namespaces":      a.AllowedNamespaces,
+			"allowed_storage_classes": a.AllowedStorageClasses,
+			"system_namespace":        a.SystemNamespace,
+			"mc_image":                a.MCImage,
+			"tunables":                a.tunables(),
+			"read_only":               a.readOnly.get(),
+			"require_clone_support":   a.RequireCloneSupport,
+			"capabilities":            a.capabilities,
+		})
+	}
+}
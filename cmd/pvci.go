@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,8 +19,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/txn2/pvci"
+	"github.com/txn2/pvci/natspub"
 	ginprometheus "github.com/zsais/go-gin-prometheus"
 	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -32,9 +35,80 @@ var (
 	modeEnv                 = getEnv("MODE", "release")
 	httpReadTimeoutEnv      = getEnv("HTTP_READ_TIMEOUT", "10")
 	httpWriteTimeoutEnv     = getEnv("HTTP_WRITE_TIMEOUT", "1200")
+	syncCreateMaxEnv        = getEnv("SYNC_CREATE_MAX_SECONDS", "0")
 	volumeOveragePercentEnv = getEnv("VOLUME_OVERAGE_PCT", "25")
+	scOveragePercentEnv     = getEnv("STORAGE_CLASS_OVERAGE_PCT", "")
 	avgMPSEnv               = getEnv("AVG_MPS", "13")
 	mcImageEnv              = getEnv("MC_IMAGE", "minio/mc:RELEASE.2020-06-26T19-56-55Z")
+	rcloneImageEnv          = getEnv("RCLONE_IMAGE", "rclone/rclone")
+	awscliImageEnv          = getEnv("AWSCLI_IMAGE", "amazon/aws-cli")
+	verifyImageEnv          = getEnv("VERIFY_IMAGE", "busybox")
+	mcAllowedTagsEnv        = getEnv("MC_IMAGE_ALLOWED_TAGS", "")
+	mcMinReleaseEnv         = getEnv("MC_IMAGE_MIN_RELEASE", "")
+	mcMaxReleaseEnv         = getEnv("MC_IMAGE_MAX_RELEASE", "")
+	defaultNamespaceEnv     = getEnv("DEFAULT_NAMESPACE", "default")
+	allowedNamespacesEnv    = getEnv("ALLOWED_NAMESPACES", "")
+	systemNamespaceEnv      = getEnv("PVCI_SYSTEM_NAMESPACE", getEnv("POD_NAMESPACE", ""))
+	allowedClassesEnv       = getEnv("ALLOWED_STORAGE_CLASSES", "")
+	cleanupOnFailureEnv     = getEnv("CLEANUP_ON_FAILURE", "false")
+	labelPrefixEnv          = getEnv("LABEL_PREFIX", "pvci.txn2.com")
+	callbackSecretEnv       = getEnv("CALLBACK_SECRET", "")
+	callbackAllowedHostsEnv = getEnv("CALLBACK_ALLOWED_HOSTS", "")
+	readOnlyEnv             = getEnv("READ_ONLY", "false")
+	readyRootEnv            = getEnv("READY_ROOT", "false")
+	adminTokenEnv           = getEnv("ADMIN_TOKEN", "")
+	natsURLEnv              = getEnv("NATS_URL", "")
+	natsSubjectEnv          = getEnv("NATS_SUBJECT", "pvci")
+	pushgatewayURLEnv       = getEnv("PUSHGATEWAY_URL", "")
+	pushgatewayJobEnv       = getEnv("PUSHGATEWAY_JOB", "")
+	pushgatewayInstanceEnv  = getEnv("PUSHGATEWAY_INSTANCE", "")
+	metricLabelsEnv         = getEnv("METRIC_LABELS", "")
+	manyObjectsThresholdEnv = getEnv("MANY_OBJECTS_THRESHOLD", "0")
+	manyObjectsOPSEnv       = getEnv("MANY_OBJECTS_OPS", "100")
+	metricLabelMaxValuesEnv = getEnv("METRIC_LABEL_MAX_VALUES", "20")
+	forceReplaceTermEnv     = getEnv("FORCE_REPLACE_TERMINATING", "false")
+	mcConfigSecretEnv       = getEnv("MC_CONFIG_SECRET", "false")
+	failOnCountMismatchEnv  = getEnv("FAIL_ON_COUNT_MISMATCH", "false")
+	requireCloneSupportEnv  = getEnv("REQUIRE_CLONE_SUPPORT", "false")
+	verifyStorageClassEnv   = getEnv("VERIFY_STORAGE_CLASS", "true")
+	stallTimeoutEnv         = getEnv("STALL_TIMEOUT", "0")
+	maxInjectorDurationEnv  = getEnv("MAX_INJECTOR_DURATION", "0")
+	cloneRetriesEnv         = getEnv("CLONE_RETRIES", "3")
+	finalizerRetriesEnv     = getEnv("FINALIZER_PATCH_RETRIES", "3")
+	reclaimOrphanedEnv      = getEnv("RECLAIM_ORPHANED_SOURCE", "false")
+	adoptInjectorJobsEnv    = getEnv("ADOPT_INJECTOR_JOBS", "true")
+	injectorLabelsEnv       = getEnv("INJECTOR_LABELS", "")
+	injectorHostAliasesEnv  = getEnv("INJECTOR_HOST_ALIASES", "")
+	transferRetriesEnv      = getEnv("INJECTOR_TRANSFER_RETRIES", "0")
+	transferTimeoutEnv      = getEnv("INJECTOR_TRANSFER_TIMEOUT", "0")
+	injectorDNSPolicyEnv    = getEnv("INJECTOR_DNS_POLICY", "")
+	injectorNameserversEnv  = getEnv("INJECTOR_NAMESERVERS", "")
+	injectorDNSSearchesEnv  = getEnv("INJECTOR_DNS_SEARCHES", "")
+	listPageSizeEnv         = getEnv("LIST_PAGE_SIZE", "0")
+	k8sBreakerThresholdEnv  = getEnv("K8S_BREAKER_THRESHOLD", "10")
+	k8sBreakerCooldownEnv   = getEnv("K8S_BREAKER_COOLDOWN", "30")
+	contentTypeStatEnv      = getEnv("CONTENT_TYPE_STAT_CONCURRENCY", "16")
+	maxBodySizeEnv          = getEnv("MAX_BODY_SIZE", "1048576")
+	s3MaxIdleConnsEnv       = getEnv("S3_MAX_IDLE_CONNS", "0")
+	s3MaxConnsPerHostEnv    = getEnv("S3_MAX_CONNS_PER_HOST", "0")
+	s3IdleConnTimeoutEnv    = getEnv("S3_IDLE_CONN_TIMEOUT", "0")
+	s3KeepAliveEnv          = getEnv("S3_KEEP_ALIVE", "0")
+	sizeCacheTTLEnv         = getEnv("SIZE_CACHE_TTL", "0")
+	storageClassCacheTTLEnv = getEnv("STORAGE_CLASS_CACHE_TTL", "60")
+	fastStartSizeEnv        = getEnv("FAST_START_SIZE", "1073741824")
+	sizeGranularityEnv      = getEnv("SIZE_GRANULARITY", "0")
+	traceHeaderEnv          = getEnv("TRACE_HEADER", "traceparent")
+	reconcileTTLEnv         = getEnv("RECONCILE_TTL", "3600")
+	reconcileIntervalEnv    = getEnv("RECONCILE_INTERVAL", "0")
+	operationRetentionEnv   = getEnv("OPERATION_RETENTION", "86400")
+	jobSweepAgeEnv          = getEnv("JOB_SWEEP_AGE", "3600")
+	jobSweepIntervalEnv     = getEnv("JOB_SWEEP_INTERVAL", "0")
+	autoGrowIntervalEnv     = getEnv("AUTOGROW_INTERVAL", "0")
+	autoGrowThresholdEnv    = getEnv("AUTOGROW_THRESHOLD", "80")
+	autoGrowPercentEnv      = getEnv("AUTOGROW_PERCENT", "50")
+	createConcurrencyEnv    = getEnv("CREATE_CONCURRENCY", "0")
+	createQueueSizeEnv      = getEnv("CREATE_QUEUE_SIZE", "100")
+	injectorAnnotationsEnv  = getEnv("INJECTOR_ANNOTATIONS", "sidecar.istio.io/inject=false,linkerd.io/inject=disabled")
 )
 
 var Version = "0.0.0"
@@ -59,12 +133,273 @@ func main() {
 		os.Exit(1)
 	}
 
+	scOveragePercent := make(map[string]int)
+	for sc, pct := range splitMap(scOveragePercentEnv) {
+		scOveragePercent[sc], err = strconv.Atoi(pct)
+		if err != nil {
+			fmt.Println("Parsing error, STORAGE_CLASS_OVERAGE_PCT must be a list of storage_class=integer.")
+			os.Exit(1)
+		}
+	}
+
 	avgMPSInt, err := strconv.Atoi(avgMPSEnv)
 	if err != nil {
 		fmt.Println("Parsing error, AVG_MPS must be an integer of megabytes.")
 		os.Exit(1)
 	}
 
+	cleanupOnFailureBool, err := strconv.ParseBool(cleanupOnFailureEnv)
+	if err != nil {
+		fmt.Println("Parsing error, CLEANUP_ON_FAILURE must be a boolean.")
+		os.Exit(1)
+	}
+
+	maxBodySizeInt, err := strconv.Atoi(maxBodySizeEnv)
+	if err != nil {
+		fmt.Println("Parsing error, MAX_BODY_SIZE must be an integer in bytes.")
+		os.Exit(1)
+	}
+
+	listPageSizeInt, err := strconv.Atoi(listPageSizeEnv)
+	if err != nil {
+		fmt.Println("Parsing error, LIST_PAGE_SIZE must be an integer.")
+		os.Exit(1)
+	}
+
+	k8sBreakerThresholdInt, err := strconv.Atoi(k8sBreakerThresholdEnv)
+	if err != nil {
+		fmt.Println("Parsing error, K8S_BREAKER_THRESHOLD must be an integer.")
+		os.Exit(1)
+	}
+
+	k8sBreakerCooldownInt, err := strconv.Atoi(k8sBreakerCooldownEnv)
+	if err != nil {
+		fmt.Println("Parsing error, K8S_BREAKER_COOLDOWN must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	contentTypeStatInt, err := strconv.Atoi(contentTypeStatEnv)
+	if err != nil {
+		fmt.Println("Parsing error, CONTENT_TYPE_STAT_CONCURRENCY must be an integer.")
+		os.Exit(1)
+	}
+
+	s3Transport := pvci.TransportTuning{}
+	for _, setting := range []struct {
+		env   string
+		value string
+		field *int
+	}{
+		{"S3_MAX_IDLE_CONNS", s3MaxIdleConnsEnv, &s3Transport.MaxIdleConns},
+		{"S3_MAX_CONNS_PER_HOST", s3MaxConnsPerHostEnv, &s3Transport.MaxConnsPerHost},
+		{"S3_IDLE_CONN_TIMEOUT", s3IdleConnTimeoutEnv, &s3Transport.IdleConnTimeout},
+		{"S3_KEEP_ALIVE", s3KeepAliveEnv, &s3Transport.KeepAlive},
+	} {
+		*setting.field, err = strconv.Atoi(setting.value)
+		if err != nil {
+			fmt.Printf("Parsing error, %s must be an integer.\n", setting.env)
+			os.Exit(1)
+		}
+	}
+
+	sizeCacheTTLInt, err := strconv.Atoi(sizeCacheTTLEnv)
+	if err != nil {
+		fmt.Println("Parsing error, SIZE_CACHE_TTL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	storageClassCacheTTLInt, err := strconv.Atoi(storageClassCacheTTLEnv)
+	if err != nil {
+		fmt.Println("Parsing error, STORAGE_CLASS_CACHE_TTL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	stallTimeoutInt, err := strconv.Atoi(stallTimeoutEnv)
+	if err != nil {
+		fmt.Println("Parsing error, STALL_TIMEOUT must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	reconcileTTLInt, err := strconv.Atoi(reconcileTTLEnv)
+	if err != nil {
+		fmt.Println("Parsing error, RECONCILE_TTL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	reconcileIntervalInt, err := strconv.Atoi(reconcileIntervalEnv)
+	if err != nil {
+		fmt.Println("Parsing error, RECONCILE_INTERVAL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	jobSweepAgeInt, err := strconv.Atoi(jobSweepAgeEnv)
+	if err != nil {
+		fmt.Println("Parsing error, JOB_SWEEP_AGE must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	jobSweepIntervalInt, err := strconv.Atoi(jobSweepIntervalEnv)
+	if err != nil {
+		fmt.Println("Parsing error, JOB_SWEEP_INTERVAL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	transferRetriesInt, err := strconv.Atoi(transferRetriesEnv)
+	if err != nil {
+		fmt.Println("Parsing error, INJECTOR_TRANSFER_RETRIES must be an integer.")
+		os.Exit(1)
+	}
+
+	transferTimeoutInt, err := strconv.Atoi(transferTimeoutEnv)
+	if err != nil {
+		fmt.Println("Parsing error, INJECTOR_TRANSFER_TIMEOUT must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	syncCreateMaxInt, err := strconv.Atoi(syncCreateMaxEnv)
+	if err != nil {
+		fmt.Println("Parsing error, SYNC_CREATE_MAX_SECONDS must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	manyObjectsThresholdInt, err := strconv.Atoi(manyObjectsThresholdEnv)
+	if err != nil {
+		fmt.Println("Parsing error, MANY_OBJECTS_THRESHOLD must be an integer.")
+		os.Exit(1)
+	}
+
+	manyObjectsOPSInt, err := strconv.Atoi(manyObjectsOPSEnv)
+	if err != nil {
+		fmt.Println("Parsing error, MANY_OBJECTS_OPS must be an integer.")
+		os.Exit(1)
+	}
+
+	metricLabelMaxValuesInt, err := strconv.Atoi(metricLabelMaxValuesEnv)
+	if err != nil {
+		fmt.Println("Parsing error, METRIC_LABEL_MAX_VALUES must be an integer.")
+		os.Exit(1)
+	}
+
+	autoGrowIntervalInt, err := strconv.Atoi(autoGrowIntervalEnv)
+	if err != nil {
+		fmt.Println("Parsing error, AUTOGROW_INTERVAL must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	autoGrowThresholdInt, err := strconv.Atoi(autoGrowThresholdEnv)
+	if err != nil {
+		fmt.Println("Parsing error, AUTOGROW_THRESHOLD must be an integer percentage.")
+		os.Exit(1)
+	}
+
+	autoGrowPercentInt, err := strconv.Atoi(autoGrowPercentEnv)
+	if err != nil {
+		fmt.Println("Parsing error, AUTOGROW_PERCENT must be an integer percentage.")
+		os.Exit(1)
+	}
+
+	operationRetentionInt, err := strconv.Atoi(operationRetentionEnv)
+	if err != nil {
+		fmt.Println("Parsing error, OPERATION_RETENTION must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	maxInjectorDurationInt, err := strconv.Atoi(maxInjectorDurationEnv)
+	if err != nil {
+		fmt.Println("Parsing error, MAX_INJECTOR_DURATION must be an integer in seconds.")
+		os.Exit(1)
+	}
+
+	cloneRetriesInt, err := strconv.Atoi(cloneRetriesEnv)
+	if err != nil {
+		fmt.Println("Parsing error, CLONE_RETRIES must be an integer.")
+		os.Exit(1)
+	}
+
+	finalizerRetriesInt, err := strconv.Atoi(finalizerRetriesEnv)
+	if err != nil {
+		fmt.Println("Parsing error, FINALIZER_PATCH_RETRIES must be an integer.")
+		os.Exit(1)
+	}
+
+	readOnlyBool, err := strconv.ParseBool(readOnlyEnv)
+	if err != nil {
+		fmt.Println("Parsing error, READ_ONLY must be a boolean.")
+		os.Exit(1)
+	}
+
+	readyRootBool, err := strconv.ParseBool(readyRootEnv)
+	if err != nil {
+		fmt.Println("Parsing error, READY_ROOT must be a boolean.")
+		os.Exit(1)
+	}
+
+	mcConfigSecretBool, err := strconv.ParseBool(mcConfigSecretEnv)
+	if err != nil {
+		fmt.Println("Parsing error, MC_CONFIG_SECRET must be a boolean.")
+		os.Exit(1)
+	}
+
+	failOnCountMismatchBool, err := strconv.ParseBool(failOnCountMismatchEnv)
+	if err != nil {
+		fmt.Println("Parsing error, FAIL_ON_COUNT_MISMATCH must be a boolean.")
+		os.Exit(1)
+	}
+
+	requireCloneSupportBool, err := strconv.ParseBool(requireCloneSupportEnv)
+	if err != nil {
+		fmt.Println("Parsing error, REQUIRE_CLONE_SUPPORT must be a boolean.")
+		os.Exit(1)
+	}
+
+	verifyStorageClassBool, err := strconv.ParseBool(verifyStorageClassEnv)
+	if err != nil {
+		fmt.Println("Parsing error, VERIFY_STORAGE_CLASS must be a boolean.")
+		os.Exit(1)
+	}
+
+	adoptInjectorJobsBool, err := strconv.ParseBool(adoptInjectorJobsEnv)
+	if err != nil {
+		fmt.Println("Parsing error, ADOPT_INJECTOR_JOBS must be a boolean.")
+		os.Exit(1)
+	}
+
+	reclaimOrphanedBool, err := strconv.ParseBool(reclaimOrphanedEnv)
+	if err != nil {
+		fmt.Println("Parsing error, RECLAIM_ORPHANED_SOURCE must be a boolean.")
+		os.Exit(1)
+	}
+
+	createConcurrencyInt, err := strconv.Atoi(createConcurrencyEnv)
+	if err != nil {
+		fmt.Println("Parsing error, CREATE_CONCURRENCY must be an integer.")
+		os.Exit(1)
+	}
+
+	createQueueSizeInt, err := strconv.Atoi(createQueueSizeEnv)
+	if err != nil {
+		fmt.Println("Parsing error, CREATE_QUEUE_SIZE must be an integer.")
+		os.Exit(1)
+	}
+
+	fastStartSizeInt, err := strconv.Atoi(fastStartSizeEnv)
+	if err != nil {
+		fmt.Println("Parsing error, FAST_START_SIZE must be an integer in bytes.")
+		os.Exit(1)
+	}
+
+	sizeGranularityInt, err := strconv.Atoi(sizeGranularityEnv)
+	if err != nil {
+		fmt.Println("Parsing error, SIZE_GRANULARITY must be an integer in bytes.")
+		os.Exit(1)
+	}
+
+	forceReplaceTermBool, err := strconv.ParseBool(forceReplaceTermEnv)
+	if err != nil {
+		fmt.Println("Parsing error, FORCE_REPLACE_TERMINATING must be a boolean.")
+		os.Exit(1)
+	}
+
 	var (
 		ip                   = flag.String("ip", ipEnv, "Server IP address to bind to.")
 		port                 = flag.String("port", portEnv, "Server port.")
@@ -74,7 +409,73 @@ func main() {
 		httpWriteTimeout     = flag.Int("httpWriteTimeout", httpWriteTimeoutInt, "HTTP write timeout")
 		volumeOveragePercent = flag.Int("volumeOveragePercent", volumeOveragePercentInt, "Volume overage percentage")
 		mcImage              = flag.String("mcImage", mcImageEnv, "MinIO client image")
+		rcloneImage          = flag.String("rcloneImage", rcloneImageEnv, "rclone image for the rclone transport")
+		awscliImage          = flag.String("awscliImage", awscliImageEnv, "AWS CLI image for the awscli transport")
+		verifyImage          = flag.String("verifyImage", verifyImageEnv, "Image counting the files landed by an injector")
 		avgMPS               = flag.Int("avgMPS", avgMPSInt, "Average transport speed in megabytes per second, use to calculate timeout estimate.")
+		defaultNamespace     = flag.String("defaultNamespace", defaultNamespaceEnv, "Namespace used when a request omits one.")
+		mcAllowedTags        = flag.String("mcImageAllowedTags", mcAllowedTagsEnv, "Comma separated list of supported mc image tags.")
+		mcMinRelease         = flag.String("mcImageMinRelease", mcMinReleaseEnv, "Oldest supported mc release tag or date (2006-01-02).")
+		mcMaxRelease         = flag.String("mcImageMaxRelease", mcMaxReleaseEnv, "Newest supported mc release tag or date (2006-01-02).")
+		allowedNamespaces    = flag.String("allowedNamespaces", allowedNamespacesEnv, "Comma separated list of namespaces requests may target, empty allows any.")
+		systemNamespace      = flag.String("systemNamespace", systemNamespaceEnv, "Namespace of PVCI's own operational objects, defaults to the pod namespace.")
+		allowedClasses       = flag.String("allowedStorageClasses", allowedClassesEnv, "Comma separated list of storage classes requests may provision, empty allows any.")
+		labelPrefix          = flag.String("labelPrefix", labelPrefixEnv, "Prefix of the label keys stamped on and used to select PVCI managed resources.")
+		cleanupOnFailure     = flag.Bool("cleanupOnFailure", cleanupOnFailureBool, "Delete the injector Job, source PVC and final PVC when a create fails or times out.")
+		forceReplaceTerm     = flag.Bool("forceReplaceTerminating", forceReplaceTermBool, "Remove finalizers from PVCI managed PVCs stuck in Terminating that block a create.")
+		stallTimeout         = flag.Int("stallTimeout", stallTimeoutInt, "Seconds without source volume growth before failing an injector, 0 disables.")
+		maxInjectorDuration  = flag.Int("maxInjectorDuration", maxInjectorDurationInt, "Hard ceiling in seconds on injector run time, 0 for none.")
+		reconcileTTL         = flag.Int("reconcileTTL", reconcileTTLInt, "Seconds after which finished injector Jobs and orphaned source PVCs are reconciled.")
+		reconcileInterval    = flag.Int("reconcileInterval", reconcileIntervalInt, "Seconds between periodic reconciles, 0 disables.")
+		jobSweepAge          = flag.Int("jobSweepAge", jobSweepAgeInt, "Seconds after which finished injector Jobs are swept.")
+		jobSweepInterval     = flag.Int("jobSweepInterval", jobSweepIntervalInt, "Seconds between sweeps of finished injector Jobs, 0 disables.")
+		autoGrowInterval     = flag.Int("autoGrowInterval", autoGrowIntervalInt, "Seconds between expansions of filling PVCs annotated pvci.txn2.com/autogrow, 0 disables.")
+		autoGrowThreshold    = flag.Int("autoGrowThreshold", autoGrowThresholdInt, "Percent of capacity used at which an autogrow PVC is expanded.")
+		autoGrowPercent      = flag.Int("autoGrowPercent", autoGrowPercentInt, "Percent an autogrow PVC's storage request grows by.")
+		operationRetention   = flag.Int("operationRetention", operationRetentionInt, "Seconds completed creates are listed by /operations.")
+		cloneRetries         = flag.Int("cloneRetries", cloneRetriesInt, "Retries of a failed final clone PVC create or bind wait.")
+		finalizerRetries     = flag.Int("finalizerPatchRetries", finalizerRetriesInt, "Retries of a failed source PVC finalizer patch after a create.")
+		mcConfigSecret       = flag.Bool("mcConfigSecret", mcConfigSecretBool, "Pass mc injector credentials in a mounted config Secret instead of the pod environment.")
+		failOnCountMismatch  = flag.Bool("failOnCountMismatch", failOnCountMismatchBool, "Fail creates landing fewer files than the objects sized.")
+		verifyStorageClass   = flag.Bool("verifyStorageClass", verifyStorageClassBool, "Reject creates naming a storage class missing from the cluster.")
+		requireCloneSupport  = flag.Bool("requireCloneSupport", requireCloneSupportBool, "Reject creates whose final storage class is not provisioned by a CSI driver rather than injecting directly.")
+		adoptInjectorJobs    = flag.Bool("adoptInjectorJobs", adoptInjectorJobsBool, "Wait on a running injector Job left by an earlier create and replace a finished one.")
+		reclaimOrphaned      = flag.Bool("reclaimOrphanedSource", reclaimOrphanedBool, "Delete PVCI managed source PVCs left by failed creates that block a retry.")
+		maxBodySize          = flag.Int("maxBodySize", maxBodySizeInt, "Max bytes read from a request body.")
+		listPageSize         = flag.Int("listPageSize", listPageSizeInt, "Max keys per object listing request, 0 for the client default.")
+		k8sBreakerThreshold  = flag.Int("k8sBreakerThreshold", k8sBreakerThresholdInt, "Consecutive failed Kubernetes API requests opening the circuit breaker, 0 disables it.")
+		k8sBreakerCooldown   = flag.Int("k8sBreakerCooldown", k8sBreakerCooldownInt, "Seconds the Kubernetes API circuit breaker stays open before probing.")
+		contentTypeStat      = flag.Int("contentTypeStatConcurrency", contentTypeStatInt, "Objects stated at once to filter by content type.")
+		sizeCacheTTL         = flag.Int("sizeCacheTTL", sizeCacheTTLInt, "Seconds to cache bucket sizes, 0 disables the cache.")
+		storageClassCacheTTL = flag.Int("storageClassCacheTTL", storageClassCacheTTLInt, "Seconds to cache storage classes read by create checks, 0 disables the cache.")
+		traceHeader          = flag.String("traceHeader", traceHeaderEnv, "Request header carrying the trace context stamped on created resources.")
+		createConcurrency    = flag.Int("createConcurrency", createConcurrencyInt, "Max async creates running at once, 0 for unbounded.")
+		createQueueSize      = flag.Int("createQueueSize", createQueueSizeInt, "Max async creates waiting for a worker before rejecting.")
+		fastStartSize        = flag.Int("fastStartSize", fastStartSizeInt, "Initial source PVC size in bytes for fast start creates.")
+		sizeGranularity      = flag.Int("sizeGranularity", sizeGranularityInt, "Round PVC storage requests up to a multiple of this many bytes, 0 for exact sizes.")
+		injectorLabels       = flag.String("injectorLabels", injectorLabelsEnv, "Comma separated key=value labels added to injector pods.")
+		injectorAnnotations  = flag.String("injectorAnnotations", injectorAnnotationsEnv, "Comma separated key=value annotations added to injector pods.")
+		transferRetries      = flag.Int("injectorTransferRetries", transferRetriesInt, "Retries of a failed injector copy, 0 keeps the copy tool's default.")
+		transferTimeout      = flag.Int("injectorTransferTimeout", transferTimeoutInt, "Seconds of I/O inactivity before rclone and awscli injectors retry a request, 0 keeps their default.")
+		injectorHostAliases  = flag.String("injectorHostAliases", injectorHostAliasesEnv, "Comma separated hostname=ip entries added to the hosts file of injector pods.")
+		injectorDNSPolicy    = flag.String("injectorDNSPolicy", injectorDNSPolicyEnv, "DNS policy of injector pods, empty for the cluster default.")
+		injectorNameservers  = flag.String("injectorNameservers", injectorNameserversEnv, "Comma separated nameservers of injector pods.")
+		injectorDNSSearches  = flag.String("injectorDNSSearches", injectorDNSSearchesEnv, "Comma separated DNS search domains of injector pods.")
+		callbackSecret       = flag.String("callbackSecret", callbackSecretEnv, "Secret used to HMAC-SHA256 sign callback bodies.")
+		callbackAllowedHosts = flag.String("callbackAllowedHosts", callbackAllowedHostsEnv, "Comma separated list of hosts, or *.domain wildcards, callback URLs may name, empty disables callbacks.")
+		readOnly             = flag.Bool("readOnly", readOnlyBool, "Start rejecting creates, deletes and other mutating requests.")
+		readyRoot            = flag.Bool("readyRoot", readyRootBool, "Respond 503 on / while the Kubernetes API is not ready.")
+		adminToken           = flag.String("adminToken", adminTokenEnv, "Bearer token of admin endpoints, empty disables them.")
+		natsURL              = flag.String("natsURL", natsURLEnv, "nats:// or tls:// URL lifecycle events are published to, empty disables publishing.")
+		natsSubject          = flag.String("natsSubject", natsSubjectEnv, "Subject prefix of published lifecycle events.")
+		pushgatewayURL       = flag.String("pushgatewayURL", pushgatewayURLEnv, "Prometheus Pushgateway URL create metrics are pushed to, empty disables pushing.")
+		pushgatewayJob       = flag.String("pushgatewayJob", pushgatewayJobEnv, "Job label of pushed metrics, empty uses the service name.")
+		pushgatewayInstance  = flag.String("pushgatewayInstance", pushgatewayInstanceEnv, "Instance label of pushed metrics, empty uses the hostname.")
+		syncCreateMax        = flag.Int("syncCreateMaxSeconds", syncCreateMaxInt, "Longest create timeout run synchronously by /create, longer creates run async, 0 disables.")
+		manyObjectsThreshold = flag.Int("manyObjectsThreshold", manyObjectsThresholdInt, "Object count at which mc injectors mirror instead of recursively copying, 0 disables.")
+		manyObjectsOPS       = flag.Int("manyObjectsOPS", manyObjectsOPSInt, "Objects per second estimated for mirroring injectors.")
+		metricLabels         = flag.String("metricLabels", metricLabelsEnv, "Comma separated keys of request metric_labels added to create metrics.")
+		metricLabelMaxValues = flag.Int("metricLabelMaxValues", metricLabelMaxValuesInt, "Distinct values allowed of each metric label.")
 	)
 	flag.Parse()
 
@@ -118,24 +519,106 @@ func main() {
 		}
 	}
 
+	// fail fast while the Kubernetes API is failing
+	var k8sBreaker *pvci.K8sBreaker
+	if *k8sBreakerThreshold > 0 {
+		k8sBreaker = pvci.NewK8sBreaker(*k8sBreakerThreshold, time.Duration(*k8sBreakerCooldown)*time.Second, logger)
+		config.Wrap(k8sBreaker.Wrap)
+	}
+
 	cs, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		logger.Fatal("unable to kubernetes.NewForConfig", zap.Error(err))
 	}
 
+	// lifecycle event publishing
+	var publisher pvci.Publisher
+	if *natsURL != "" {
+		publisher, err = natspub.New(*natsURL)
+		if err != nil {
+			logger.Fatal("unable to configure NATS publisher", zap.Error(err))
+		}
+	}
+
 	// get api
 	api, err := pvci.NewApi(&pvci.Config{
 		Service:              Service,
 		Version:              Version,
 		VolumeOveragePercent: *volumeOveragePercent,
+		StorageClassOverage:  scOveragePercent,
 		MCImage:              *mcImage,
+		RcloneImage:          *rcloneImage,
+		AWSCLIImage:          *awscliImage,
+		VerifyImage:          *verifyImage,
 		AvgMPS:               *avgMPS,
-		Log:                  logger,
-		Cs:                   cs,
+		DefaultNamespace:     *defaultNamespace,
+		AllowedNamespaces:    splitList(*allowedNamespaces),
+		MCImageAllowedTags:   splitList(*mcAllowedTags),
+		MCImageMinRelease:    *mcMinRelease,
+		MCImageMaxRelease:    *mcMaxRelease,
+		LabelPrefix:          *labelPrefix,
+		CleanupOnFailure:     *cleanupOnFailure,
+		CallbackSecret:       *callbackSecret,
+		ReadOnly:             *readOnly,
+		ReadyRoot:            *readyRoot,
+		AdminToken:           *adminToken,
+		Publisher:            publisher,
+		PublishSubject:       *natsSubject,
+		K8sBreaker:           k8sBreaker,
+
+		ContentTypeStatConcurrency: *contentTypeStat,
+
+		AllowedStorageClasses:   splitList(*allowedClasses),
+		CallbackAllowedHosts:    splitList(*callbackAllowedHosts),
+		SystemNamespace:         *systemNamespace,
+		ListPageSize:            *listPageSize,
+		MaxBodySize:             int64(*maxBodySize),
+		S3Transport:             s3Transport,
+		SizeCacheTTL:            time.Duration(*sizeCacheTTL) * time.Second,
+		StorageClassCacheTTL:    time.Duration(*storageClassCacheTTL) * time.Second,
+		FastStartSize:           int64(*fastStartSize),
+		SizeGranularity:         int64(*sizeGranularity),
+		TraceHeader:             *traceHeader,
+		CreateConcurrency:       *createConcurrency,
+		CreateQueueSize:         *createQueueSize,
+		InjectorLabels:          splitMap(*injectorLabels),
+		InjectorAnnotations:     splitMap(*injectorAnnotations),
+		InjectorTransferRetries: *transferRetries,
+		InjectorTransferTimeout: int64(*transferTimeout),
+		InjectorHostAliases:     hostAliases(splitMap(*injectorHostAliases)),
+		InjectorDNSPolicy:       coreV1.DNSPolicy(*injectorDNSPolicy),
+		InjectorDNSConfig:       dnsConfig(splitList(*injectorNameservers), splitList(*injectorDNSSearches)),
+		ForceReplaceTerminating: *forceReplaceTerm,
+		MCConfigSecret:          *mcConfigSecret,
+		FailOnCountMismatch:     *failOnCountMismatch,
+		RequireCloneSupport:     *requireCloneSupport,
+		VerifyStorageClass:      *verifyStorageClass,
+		PushgatewayURL:          *pushgatewayURL,
+		PushgatewayJob:          *pushgatewayJob,
+		PushgatewayInstance:     *pushgatewayInstance,
+		SyncCreateMaxSeconds:    int64(*syncCreateMax),
+		ManyObjectsThreshold:    int64(*manyObjectsThreshold),
+		ManyObjectsOPS:          *manyObjectsOPS,
+		MetricLabels:            splitList(*metricLabels),
+		MetricLabelMaxValues:    *metricLabelMaxValues,
+		AutoGrowThreshold:       *autoGrowThreshold,
+		AutoGrowPercent:         *autoGrowPercent,
+		ReclaimOrphanedSource:   *reclaimOrphaned,
+		AdoptInjectorJobs:       *adoptInjectorJobs,
+		CloneRetries:            *cloneRetries,
+		FinalizerPatchRetries:   *finalizerRetries,
+		StallTimeout:            time.Duration(*stallTimeout) * time.Second,
+		MaxInjectorDuration:     time.Duration(*maxInjectorDuration) * time.Second,
+		ReconcileTTL:            time.Duration(*reconcileTTL) * time.Second,
+		OperationRetention:      time.Duration(*operationRetention) * time.Second,
+		JobSweepAge:             time.Duration(*jobSweepAge) * time.Second,
+		Log:                     logger,
+		Cs:                      cs,
 	})
 	if err != nil {
 		logger.Fatal("Error getting API.", zap.Error(err))
 	}
+	prometheus.MustRegister(api.Collector())
 
 	gin.SetMode(gin.ReleaseMode)
 	if *mode == "debug" {
@@ -165,18 +648,83 @@ func main() {
 	// status
 	r.GET("/", api.OkHandler(Version, *mode, Service))
 
+	// readiness to serve creates
+	r.GET("/readyz", api.ReadyHandler())
+
+	// non-secret configuration and detected cluster capabilities
+	r.GET("/config", api.ConfigHandler())
+
 	// get bucket size
 	r.POST("/size", api.GetSizeHandler())
+	r.GET("/size", api.GetSizeQueryHandler())
+
+	// pre-size buckets into the size cache
+	r.POST("/prewarm", api.PrewarmHandler())
+
+	// validate S3 credentials
+	r.POST("/check-credentials", api.CheckCredentialsHandler())
+
+	// copy a prefix between buckets server-side
+	r.POST("/s3-copy", api.RequireWritable(), api.S3CopyHandler())
+
+	// estimate injection time
+	r.POST("/estimate", api.EstimateHandler())
+
+	// presigned object URLs instead of a volume
+	r.POST("/presign", api.Gzip(), api.PresignHandler())
 
 	// create pvc
-	r.POST("/create", api.CreatePVCHandler())
+	r.POST("/create", api.RequireWritable(), api.CreatePVCHandler())
 
 	// create pvc
-	r.POST("/create-async", api.CreatePVCAsyncHandler())
+	r.POST("/create-async", api.RequireWritable(), api.CreatePVCAsyncHandler())
+
+	// create pvc and wait for completion up to a deadline
+	r.POST("/create-wait", api.RequireWritable(), api.CreatePVCWaitHandler())
 
 	// get status
 	r.POST("/status", api.GetStatusHandler())
 
+	// gather the evidence of a failed create
+	r.POST("/diagnose", api.DiagnoseHandler())
+
+	// get status of many volumes
+	r.POST("/status-batch", api.Gzip(), api.GetStatusBatchHandler())
+
+	// delete pvc
+	r.POST("/delete", api.RequireWritable(), api.DeleteHandler())
+
+	// clean up orphaned source PVCs and finished injector Jobs
+	r.POST("/reconcile", api.RequireWritable(), api.Gzip(), api.ReconcileHandler())
+
+	// warm injector image caches on nodes
+	r.POST("/prepull", api.RequireWritable(), api.PrepullHandler())
+
+	// list running and completed creates
+	r.GET("/operations", api.Gzip(), api.OperationsHandler())
+
+	// toggle read-only mode for maintenance
+	r.GET("/read-only", api.RequireAdmin(), api.ReadOnlyHandler())
+	r.POST("/read-only", api.RequireAdmin(), api.ReadOnlyHandler())
+
+	// tune AVG_MPS, overage and injector timeouts at runtime
+	r.POST("/admin/config", api.RequireAdmin(), api.TunablesHandler())
+
+	// periodic reconcile (run in go routine)
+	if *reconcileInterval > 0 {
+		go api.ReconcileLoop(time.Duration(*reconcileInterval) * time.Second)
+	}
+
+	// periodic sweep of finished injector Jobs (run in go routine)
+	if *jobSweepInterval > 0 {
+		go api.SweepLoop(time.Duration(*jobSweepInterval) * time.Second)
+	}
+
+	// periodic expansion of filling autogrow PVCs (run in go routine)
+	if *autoGrowInterval > 0 {
+		go api.AutoGrowLoop(time.Duration(*autoGrowInterval) * time.Second)
+	}
+
 	// metrics server (run in go routine)
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
@@ -220,3 +768,59 @@ func getEnv(key, fallback string) string {
 
 	return value
 }
+
+// splitList splits a comma separated string into a list of
+// trimmed, non-empty values.
+func splitList(value string) []string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// splitMap splits a comma separated list of key=value pairs into a map.
+func splitMap(value string) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range splitList(value) {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return m
+}
+
+// hostAliases groups a map of hostnames to IPs into host aliases,
+// ordered by IP.
+func hostAliases(hosts map[string]string) []coreV1.HostAlias {
+	byIP := make(map[string][]string)
+	for hostname, ip := range hosts {
+		byIP[ip] = append(byIP[ip], hostname)
+	}
+
+	aliases := make([]coreV1.HostAlias, 0, len(byIP))
+	for ip, hostnames := range byIP {
+		sort.Strings(hostnames)
+		aliases = append(aliases, coreV1.HostAlias{IP: ip, Hostnames: hostnames})
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].IP < aliases[j].IP })
+
+	return aliases
+}
+
+// dnsConfig returns a pod DNS config of nameservers and search
+// domains, or nil when both are empty.
+func dnsConfig(nameservers []string, searches []string) *coreV1.PodDNSConfig {
+	if len(nameservers) == 0 && len(searches) == 0 {
+		return nil
+	}
+
+	return &coreV1.PodDNSConfig{Nameservers: nameservers, Searches: searches}
+}
@@ -0,0 +1,73 @@
+package pvci
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MaxReportedCollisions limits the keys listed in a KEY_COLLISION error.
+const MaxReportedCollisions = 10
+
+// keyCollisions detects object keys that are also directories of other
+// keys, which can not both land on a filesystem. Keys must be added in
+// the lexical order S3 lists them; keys sharing a prefix are listed
+// together, so only the files whose prefix range is still open are
+// kept. Directories are separated by delimiter, "/" when empty.
+type keyCollisions struct {
+	delimiter string
+	open      []string
+	keys      []string
+	count     int
+}
+
+// add checks a key against the open files, then opens it.
+func (kc *keyCollisions) add(key string) {
+	delimiter := kc.delimiter
+	if delimiter == "" {
+		delimiter = "/"
+	}
+
+	for len(kc.open) > 0 {
+		file := kc.open[len(kc.open)-1]
+		dir := file + delimiter
+
+		if strings.HasPrefix(key, dir) {
+			kc.count += 1
+			if len(kc.keys) < MaxReportedCollisions {
+				kc.keys = append(kc.keys, file)
+			}
+			kc.open = kc.open[:len(kc.open)-1]
+			break
+		}
+
+		// the listing has passed the keys under the file
+		if key > dir {
+			kc.open = kc.open[:len(kc.open)-1]
+			continue
+		}
+
+		break
+	}
+
+	// directory markers are not files
+	if !strings.HasSuffix(key, delimiter) {
+		kc.open = append(kc.open, key)
+	}
+}
+
+// err returns a KEY_COLLISION Error listing the colliding keys,
+// or nil when there are none.
+func (kc *keyCollisions) err() error {
+	if kc.count == 0 {
+		return nil
+	}
+
+	more := ""
+	if kc.count > len(kc.keys) {
+		more = " and others"
+	}
+
+	return newError(ErrCodeKeyCollision, http.StatusConflict,
+		"%d object keys are also directories of other keys and can not be copied to a filesystem: %s%s",
+		kc.count, strings.Join(kc.keys, ", "), more)
+}
@@ -0,0 +1,134 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConsumerVerifyTimeout is the number of seconds a consumer verification
+// pod is given to mount the final PVC and list its contents.
+const ConsumerVerifyTimeout = 120
+
+// ConsumerVerifyInterval is the number of seconds between checks of a
+// consumer verification pod.
+const ConsumerVerifyInterval = 2
+
+// consumerScript fails when the mounted volume has no entries.
+const consumerScript = `[ -n "$(ls -A /data)" ] || { echo "no data found on the volume" > /dev/termination-log; exit 1; }`
+
+// verifyConsumable runs a pod mounting the final PVC of a create
+// read-only, as downstream workloads would, failing when the pod can
+// not mount the volume or finds it empty. The pod is always removed.
+func (a *API) verifyConsumable(pvcRequestConfig PVCRequestConfig) error {
+	ctx := context.Background()
+	podClient := a.Cs.CoreV1().Pods(pvcRequestConfig.Namespace)
+
+	labels := a.volLabels(pvcRequestConfig.Name)
+	labels[a.labelKey("job")] = "consumer"
+
+	podName := fmt.Sprintf("%s-consumer", pvcRequestConfig.Name)
+
+	_, err := podClient.Create(ctx, &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        podName,
+			Namespace:   pvcRequestConfig.Namespace,
+			Labels:      labels,
+			Annotations: a.InjectorAnnotations,
+		},
+		Spec: coreV1.PodSpec{
+			RestartPolicy: coreV1.RestartPolicyNever,
+			Volumes: []coreV1.Volume{
+				{
+					Name: "data",
+					VolumeSource: coreV1.VolumeSource{
+						PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvcRequestConfig.Name,
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Containers: []coreV1.Container{
+				{
+					Name:    "consumer",
+					Image:   a.VerifyImage,
+					Command: []string{"sh", "-c", consumerScript},
+					VolumeMounts: []coreV1.VolumeMount{
+						{
+							MountPath: "/data",
+							Name:      "data",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+		},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := podClient.Delete(ctx, podName, metaV1.DeleteOptions{})
+		if err != nil {
+			a.Log.Error("unable to delete consumer pod",
+				zap.String("namespace", pvcRequestConfig.Namespace),
+				zap.String("name", podName),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	waiting := "pod not scheduled"
+	for i := 0; i < ConsumerVerifyTimeout/ConsumerVerifyInterval; i++ {
+		pod, err := podClient.Get(ctx, podName, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		switch pod.Status.Phase {
+		case coreV1.PodSucceeded:
+			return nil
+		case coreV1.PodFailed:
+			return fmt.Errorf("PVC %s failed consumer verification: %s", pvcRequestConfig.Name, terminationMessage(pod))
+		}
+
+		for _, cond := range pod.Status.Conditions {
+			if cond.Status != coreV1.ConditionTrue && cond.Message != "" {
+				waiting = cond.Message
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Message != "" {
+				waiting = cs.State.Waiting.Message
+			}
+		}
+
+		time.Sleep(ConsumerVerifyInterval * time.Second)
+	}
+
+	return fmt.Errorf("PVC %s failed consumer verification, not mounted in %d seconds: %s",
+		pvcRequestConfig.Name, ConsumerVerifyTimeout, waiting)
+}
+
+// terminationMessage returns the termination message of the first
+// terminated container of a pod.
+func terminationMessage(pod *coreV1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated != nil {
+			msg := strings.TrimSpace(cs.State.Terminated.Message)
+			if msg == "" {
+				msg = cs.State.Terminated.Reason
+			}
+			return msg
+		}
+	}
+
+	return "pod failed"
+}
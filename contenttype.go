@@ -0,0 +1,118 @@
+package pvci
+
+import (
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// DefaultContentTypeStatConcurrency is the number of objects stated at
+// once to filter by content type when Config.ContentTypeStatConcurrency
+// is not set.
+const DefaultContentTypeStatConcurrency = 16
+
+// checkContentTypes validates the ContentTypes filter of a
+// PVCRequestConfig. Filtering lists and stats the latest objects, so
+// it can not be combined with inventory sizing, S3AsOf or a populator.
+func checkContentTypes(pvcRequestConfig PVCRequestConfig) error {
+	if len(pvcRequestConfig.ContentTypes) == 0 {
+		return nil
+	}
+
+	for _, contentType := range pvcRequestConfig.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			return badRequest("content_types %q is not a media type: %s", contentType, err.Error())
+		}
+	}
+
+	switch {
+	case pvcRequestConfig.SizeSource == SizeSourceInventory:
+		return badRequest("content_types can not be combined with size_source %s", SizeSourceInventory)
+	case pvcRequestConfig.S3AsOf != "":
+		return badRequest("content_types can not be combined with s3_as_of")
+	case pvcRequestConfig.Populator != nil:
+		return badRequest("content_types can not be combined with a populator")
+	}
+
+	return nil
+}
+
+// matchesContentType reports whether the media type of contentType,
+// ignoring parameters such as charset, is one of contentTypes.
+func matchesContentType(contentTypes []string, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, ct := range contentTypes {
+		want, _, _ := mime.ParseMediaType(ct)
+		if strings.EqualFold(want, mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listContentTypes implements listObjects for a ContentTypes filter.
+// Listings do not carry content types, so objects are stated in batches
+// of ContentTypeStatConcurrency and those matching are passed to fn in
+// listing order.
+func (a *API) listContentTypes(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig, fn func(object minio.ObjectInfo) error) error {
+	unfiltered := pvcRequestConfig
+	unfiltered.ContentTypes = nil
+
+	batch := make([]minio.ObjectInfo, 0, a.ContentTypeStatConcurrency)
+
+	flush := func() error {
+		stats := make([]minio.ObjectInfo, len(batch))
+		errs := make([]error, len(batch))
+
+		wg := sync.WaitGroup{}
+		for i := range batch {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				stats[i], errs[i] = minioClient.StatObject(pvcRequestConfig.S3Bucket, batch[i].Key, minio.StatObjectOptions{})
+			}(i)
+		}
+		wg.Wait()
+
+		for i, object := range batch {
+			if errs[i] != nil {
+				return errs[i]
+			}
+
+			if !matchesContentType(pvcRequestConfig.ContentTypes, stats[i].ContentType) {
+				continue
+			}
+
+			object.ContentType = stats[i].ContentType
+			err := fn(object)
+			if err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+
+		return nil
+	}
+
+	err := a.listObjects(minioClient, unfiltered, func(object minio.ObjectInfo) error {
+		batch = append(batch, object)
+		if len(batch) < cap(batch) {
+			return nil
+		}
+
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
@@ -0,0 +1,142 @@
+package pvci
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v6"
+)
+
+// Credential check results.
+const (
+	CredentialsValid        = "valid"
+	CredentialsInvalid      = "invalid-credentials"
+	CredentialsUnreachable  = "endpoint-unreachable"
+	CredentialsInaccessible = "bucket-inaccessible"
+)
+
+// CredentialCheck is the result of CheckCredentials.
+type CredentialCheck struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CheckCredentialsHandler used by the HTTP POST endpoint /check-credentials
+// to validate the S3 configuration of a request.
+func (a *API) CheckCredentialsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, a.CheckCredentials(pvcRequestConfig.S3Config))
+	}
+}
+
+// CheckCredentials validates an S3Config with a single authenticated
+// request, a HEAD on the bucket or, without a bucket, a bucket listing.
+func (a *API) CheckCredentials(s3Config S3Config) CredentialCheck {
+	minioClient, err := a.getMinIOClient(PVCRequestConfig{S3Config: s3Config})
+	if err != nil {
+		return CredentialCheck{Result: CredentialsUnreachable, Error: err.Error()}
+	}
+
+	if s3Config.S3Bucket == "" {
+		_, err = minioClient.ListBuckets()
+		return credentialCheck(err)
+	}
+
+	exists, err := minioClient.BucketExists(s3Config.S3Bucket)
+	if err == nil && !exists {
+		return CredentialCheck{Result: CredentialsInaccessible, Error: "bucket " + s3Config.S3Bucket + " does not exist"}
+	}
+
+	return credentialCheck(err)
+}
+
+// credentialCheck classifies the error of a credential check request.
+func credentialCheck(err error) CredentialCheck {
+	if err == nil {
+		return CredentialCheck{Result: CredentialsValid}
+	}
+
+	s3Err := minio.ToErrorResponse(err)
+	switch {
+	case s3Err.Code == "InvalidAccessKeyId" || s3Err.Code == "SignatureDoesNotMatch":
+		return CredentialCheck{Result: CredentialsInvalid, Error: err.Error()}
+	case s3Err.StatusCode == http.StatusUnauthorized:
+		return CredentialCheck{Result: CredentialsInvalid, Error: err.Error()}
+	case s3Err.Code != "" || s3Err.StatusCode != 0:
+		// the object store answered, refusing the bucket
+		return CredentialCheck{Result: CredentialsInaccessible, Error: err.Error()}
+	}
+
+	return CredentialCheck{Result: CredentialsUnreachable, Error: err.Error()}
+}
+
+// listCredentials returns the access key and secret sizing lists the
+// bucket with, S3ListKey and S3ListSecret when set, otherwise S3Key
+// and S3Secret. Empty credentials make anonymous requests.
+func (s3Config S3Config) listCredentials() (string, string) {
+	if s3Config.S3ListAnonymous {
+		return "", ""
+	}
+	if s3Config.S3ListKey != "" || s3Config.S3ListSecret != "" {
+		return s3Config.S3ListKey, s3Config.S3ListSecret
+	}
+
+	return s3Config.S3Key, s3Config.S3Secret
+}
+
+// transferCredentials returns the access key and secret the injector
+// copies objects with, S3TransferKey and S3TransferSecret when set,
+// otherwise S3Key and S3Secret. Empty credentials make anonymous
+// requests.
+func (s3Config S3Config) transferCredentials() (string, string) {
+	if s3Config.S3TransferAnonymous {
+		return "", ""
+	}
+	if s3Config.S3TransferKey != "" || s3Config.S3TransferSecret != "" {
+		return s3Config.S3TransferKey, s3Config.S3TransferSecret
+	}
+
+	return s3Config.S3Key, s3Config.S3Secret
+}
+
+// checkListCredentials validates that listing resolves to a complete
+// access key and secret or is anonymous.
+func checkListCredentials(s3Config S3Config) error {
+	if s3Config.S3ListAnonymous && (s3Config.S3ListKey != "" || s3Config.S3ListSecret != "") {
+		return badRequest("s3_list_anonymous can not be combined with s3_list_key or s3_list_secret")
+	}
+
+	key, secret := s3Config.listCredentials()
+	if (key == "") != (secret == "") {
+		return badRequest("listing requires both an access key and secret, or neither for anonymous listing")
+	}
+
+	return nil
+}
+
+// checkCredentials validates the listing and transfer credentials of
+// an S3Config.
+func checkCredentials(s3Config S3Config) error {
+	err := checkListCredentials(s3Config)
+	if err != nil {
+		return err
+	}
+
+	if s3Config.S3TransferAnonymous && (s3Config.S3TransferKey != "" || s3Config.S3TransferSecret != "") {
+		return badRequest("s3_transfer_anonymous can not be combined with s3_transfer_key or s3_transfer_secret")
+	}
+
+	key, secret := s3Config.transferCredentials()
+	if (key == "") != (secret == "") {
+		return badRequest("transfer requires both an access key and secret, or neither for anonymous transfer")
+	}
+
+	return nil
+}
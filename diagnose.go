@@ -0,0 +1,247 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Limits of the evidence gathered by Diagnose.
+const (
+	DiagnoseLogLines  = 50
+	DiagnoseMaxEvents = 50
+	DiagnoseMaxPods   = 3
+)
+
+// Diagnosis gathers the evidence of a failed create in one place: the
+// last failed create of the volume, its status, the injector pods with
+// the reasons their containers terminated and the tail of their logs,
+// and the recent events of the PVCs, Job and pods. Evidence that could
+// not be gathered is reported in Errors.
+type Diagnosis struct {
+	Namespace   string            `json:"namespace"`
+	Name        string            `json:"name"`
+	LastFailure *Operation        `json:"last_failure,omitempty"`
+	Status      StatusReport      `json:"status"`
+	Pods        []PodDiagnosis    `json:"pods"`
+	Events      []DiagnosticEvent `json:"events"`
+	Errors      []string          `json:"errors"`
+}
+
+// PodDiagnosis describes an injector pod and its containers.
+type PodDiagnosis struct {
+	Name       string               `json:"name"`
+	Node       string               `json:"node"`
+	Phase      coreV1.PodPhase      `json:"phase"`
+	Reason     string               `json:"reason"`
+	Message    string               `json:"message"`
+	Containers []ContainerDiagnosis `json:"containers"`
+}
+
+// ContainerDiagnosis describes the state of an injector container, how
+// it last terminated and the tail of its logs.
+type ContainerDiagnosis struct {
+	Name                   string `json:"name"`
+	State                  string `json:"state"`
+	Reason                 string `json:"reason"`
+	Message                string `json:"message"`
+	RestartCount           int32  `json:"restart_count"`
+	LastTerminationReason  string `json:"last_termination_reason"`
+	LastTerminationMessage string `json:"last_termination_message"`
+	ExitCode               int32  `json:"exit_code"`
+	LogsTail               string `json:"logs_tail"`
+}
+
+// DiagnosticEvent is a Kubernetes Event of a resource of a create.
+type DiagnosticEvent struct {
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// DiagnoseHandler used by the HTTP POST /diagnose endpoint returns a
+// Diagnosis of the volume named in the body, as for /status.
+func (a *API) DiagnoseHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		diagnosis, err := a.Diagnose(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, diagnosis)
+	}
+}
+
+// Diagnose gathers a Diagnosis of a volume, consolidating the kubectl
+// describe, get events and logs commands run after a failed create.
+// Logs are tailed for the DiagnoseMaxPods newest injector pods.
+func (a *API) Diagnose(pvcRequestConfig PVCRequestConfig) (Diagnosis, error) {
+	ctx := context.Background()
+
+	err := a.resolveNamespace(&pvcRequestConfig)
+	if err != nil {
+		return Diagnosis{}, err
+	}
+
+	namespace := pvcRequestConfig.Namespace
+	name := pvcRequestConfig.Name
+
+	d := Diagnosis{
+		Namespace: namespace,
+		Name:      name,
+		Pods:      make([]PodDiagnosis, 0),
+		Events:    make([]DiagnosticEvent, 0),
+		Errors:    make([]string, 0),
+	}
+
+	for _, op := range a.operations.list(namespace, OperationFailed) {
+		if op.Name == name && op.Operation == "create" {
+			op := op
+			d.LastFailure = &op
+			break
+		}
+	}
+
+	d.Status, err = a.GetStatus(pvcRequestConfig)
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("status: %s", err.Error()))
+	}
+
+	pods, err := a.Cs.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: a.injectorSelector(name),
+	})
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("injector pods: %s", err.Error()))
+		pods = &coreV1.PodList{}
+	}
+
+	sort.SliceStable(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+
+	// events of the PVCs, Job and pods of the create
+	involved := map[string]bool{
+		name:               true,
+		name + "-src":      true,
+		name + "-injector": true,
+	}
+
+	for i, pod := range pods.Items {
+		involved[pod.Name] = true
+		if i < DiagnoseMaxPods {
+			d.Pods = append(d.Pods, a.diagnosePod(ctx, pod, &d))
+		}
+	}
+
+	events, err := a.Cs.CoreV1().Events(namespace).List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("events: %s", err.Error()))
+		return d, nil
+	}
+
+	for _, event := range events.Items {
+		if !involved[event.InvolvedObject.Name] {
+			continue
+		}
+
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+
+		d.Events = append(d.Events, DiagnosticEvent{
+			Kind:     event.InvolvedObject.Kind,
+			Name:     event.InvolvedObject.Name,
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: lastSeen,
+		})
+	}
+
+	sort.SliceStable(d.Events, func(i, j int) bool {
+		return d.Events[i].LastSeen.After(d.Events[j].LastSeen)
+	})
+	if len(d.Events) > DiagnoseMaxEvents {
+		d.Events = d.Events[:DiagnoseMaxEvents]
+	}
+
+	return d, nil
+}
+
+// diagnosePod describes an injector pod, tailing the logs of each of
+// its containers. Logs that can not be read are reported in the
+// Errors of the Diagnosis.
+func (a *API) diagnosePod(ctx context.Context, pod coreV1.Pod, d *Diagnosis) PodDiagnosis {
+	pd := PodDiagnosis{
+		Name:       pod.Name,
+		Node:       pod.Spec.NodeName,
+		Phase:      pod.Status.Phase,
+		Reason:     pod.Status.Reason,
+		Message:    pod.Status.Message,
+		Containers: make([]ContainerDiagnosis, 0),
+	}
+
+	statuses := append(append([]coreV1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		cd := ContainerDiagnosis{Name: status.Name, RestartCount: status.RestartCount}
+
+		// the current termination, or the last of a restarted container
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+
+		switch {
+		case status.State.Running != nil:
+			cd.State = "running"
+		case status.State.Waiting != nil:
+			cd.State = "waiting"
+			cd.Reason = status.State.Waiting.Reason
+			cd.Message = status.State.Waiting.Message
+		case status.State.Terminated != nil:
+			cd.State = "terminated"
+			cd.Reason = status.State.Terminated.Reason
+			cd.Message = status.State.Terminated.Message
+		}
+
+		if terminated != nil {
+			cd.LastTerminationReason = terminated.Reason
+			cd.LastTerminationMessage = terminated.Message
+			cd.ExitCode = terminated.ExitCode
+		}
+
+		tailLines := int64(DiagnoseLogLines)
+		logs, err := a.Cs.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &coreV1.PodLogOptions{
+			Container: status.Name,
+			TailLines: &tailLines,
+		}).DoRaw(ctx)
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("logs of %s/%s: %s", pod.Name, status.Name, err.Error()))
+		}
+		cd.LogsTail = string(logs)
+
+		pd.Containers = append(pd.Containers, cd)
+	}
+
+	return pd
+}
@@ -0,0 +1,73 @@
+package pvci
+
+import (
+	"net"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// dnsPolicies are the pod DNS policies an injector may use.
+var dnsPolicies = map[coreV1.DNSPolicy]bool{
+	coreV1.DNSClusterFirstWithHostNet: true,
+	coreV1.DNSClusterFirst:            true,
+	coreV1.DNSDefault:                 true,
+	coreV1.DNSNone:                    true,
+}
+
+// injectorDNS returns the host aliases, DNS policy and DNS config of
+// the injector pod of a PVCRequestConfig. Host aliases of the request
+// are added to the configured InjectorHostAliases, its DNS policy and
+// config replace the configured ones.
+func (a *API) injectorDNS(pvcRequestConfig PVCRequestConfig) ([]coreV1.HostAlias, coreV1.DNSPolicy, *coreV1.PodDNSConfig) {
+	hostAliases := make([]coreV1.HostAlias, 0)
+	hostAliases = append(hostAliases, a.InjectorHostAliases...)
+	hostAliases = append(hostAliases, pvcRequestConfig.HostAliases...)
+
+	dnsPolicy := a.InjectorDNSPolicy
+	if pvcRequestConfig.DNSPolicy != "" {
+		dnsPolicy = pvcRequestConfig.DNSPolicy
+	}
+
+	dnsConfig := a.InjectorDNSConfig
+	if pvcRequestConfig.DNSConfig != nil {
+		dnsConfig = pvcRequestConfig.DNSConfig
+	}
+
+	return hostAliases, dnsPolicy, dnsConfig
+}
+
+// checkInjectorDNS validates the host aliases and DNS settings of the
+// injector pod of a PVCRequestConfig. The None policy requires a DNS
+// config with nameservers.
+func (a *API) checkInjectorDNS(pvcRequestConfig PVCRequestConfig) error {
+	hostAliases, dnsPolicy, dnsConfig := a.injectorDNS(pvcRequestConfig)
+
+	for _, hostAlias := range hostAliases {
+		if net.ParseIP(hostAlias.IP) == nil {
+			return badRequest("host alias IP %q is not an IP address", hostAlias.IP)
+		}
+		if len(hostAlias.Hostnames) == 0 {
+			return badRequest("host alias %s has no hostnames", hostAlias.IP)
+		}
+	}
+
+	if dnsPolicy != "" && !dnsPolicies[dnsPolicy] {
+		return badRequest("dns_policy must be one of ClusterFirstWithHostNet, ClusterFirst, Default or None")
+	}
+
+	if dnsPolicy == coreV1.DNSNone && (dnsConfig == nil || len(dnsConfig.Nameservers) == 0) {
+		return badRequest("dns_policy None requires dns_config nameservers")
+	}
+
+	return nil
+}
+
+// injectorDNSPod adds the host aliases and DNS settings of a
+// PVCRequestConfig to an injector pod spec.
+func (a *API) injectorDNSPod(podSpec *coreV1.PodSpec, pvcRequestConfig PVCRequestConfig) {
+	hostAliases, dnsPolicy, dnsConfig := a.injectorDNS(pvcRequestConfig)
+
+	podSpec.HostAliases = append(podSpec.HostAliases, hostAliases...)
+	podSpec.DNSPolicy = dnsPolicy
+	podSpec.DNSConfig = dnsConfig
+}
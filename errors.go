@@ -0,0 +1,153 @@
+package pvci
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v6"
+	"go.uber.org/zap"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Error codes returned in the "code" field of error responses.
+const (
+	ErrCodeBadRequest           = "BAD_REQUEST"
+	ErrCodeUnauthorized         = "UNAUTHORIZED"
+	ErrCodeForbidden            = "FORBIDDEN"
+	ErrCodeNotFound             = "NOT_FOUND"
+	ErrCodeConflict             = "CONFLICT"
+	ErrCodeKeyCollision         = "KEY_COLLISION"
+	ErrCodeCloneIncompatible    = "CLONE_INCOMPATIBLE"
+	ErrCodeStorageClassNotFound = "STORAGE_CLASS_NOT_FOUND"
+	ErrCodeCountMismatch        = "COUNT_MISMATCH"
+	ErrCodeInjectorOOM          = "INJECTOR_OOM"
+	ErrCodeTooLarge             = "REQUEST_TOO_LARGE"
+	ErrCodeUnavailable          = "UNAVAILABLE"
+	ErrCodeK8sUnavailable       = "K8S_UNAVAILABLE"
+	ErrCodeReadOnly             = "READ_ONLY"
+	ErrCodeInternal             = "INTERNAL"
+)
+
+// Error is an error carrying a code and the HTTP status
+// handlers respond with. A RetryAfter is sent as the Retry-After
+// header of the response.
+type Error struct {
+	Code       string
+	Status     int
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError returns an Error with a formatted message.
+func newError(code string, status int, format string, args ...interface{}) error {
+	return &Error{Code: code, Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+// badRequest returns an Error for invalid requests.
+func badRequest(format string, args ...interface{}) error {
+	return newError(ErrCodeBadRequest, http.StatusBadRequest, format, args...)
+}
+
+// forbidden returns an Error for requests not permitted by configuration.
+func forbidden(format string, args ...interface{}) error {
+	return newError(ErrCodeForbidden, http.StatusForbidden, format, args...)
+}
+
+// conflict returns an Error for requests conflicting with existing resources.
+func conflict(format string, args ...interface{}) error {
+	return newError(ErrCodeConflict, http.StatusConflict, format, args...)
+}
+
+// unavailable returns an Error for requests rejected while at capacity.
+func unavailable(format string, args ...interface{}) error {
+	return newError(ErrCodeUnavailable, http.StatusServiceUnavailable, format, args...)
+}
+
+// ErrorStatus classifies an error into a code and HTTP status. Typed
+// errors carry their own; Kubernetes and S3 API errors are mapped by
+// their reason, and anything else is an internal error.
+func ErrorStatus(err error) (string, int) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, e.Status
+	}
+
+	var statusErr apiErrors.APIStatus
+	if errors.As(err, &statusErr) {
+		switch {
+		case apiErrors.IsNotFound(err):
+			return ErrCodeNotFound, http.StatusNotFound
+		case apiErrors.IsAlreadyExists(err), apiErrors.IsConflict(err):
+			return ErrCodeConflict, http.StatusConflict
+		case apiErrors.IsInvalid(err), apiErrors.IsBadRequest(err):
+			return ErrCodeBadRequest, http.StatusBadRequest
+		}
+		return ErrCodeInternal, http.StatusInternalServerError
+	}
+
+	s3Err := minio.ToErrorResponse(err)
+	if s3Err.Code != "" {
+		switch {
+		case s3Err.Code == "NoSuchBucket" || s3Err.Code == "NoSuchKey":
+			return ErrCodeNotFound, http.StatusNotFound
+		case s3Err.StatusCode >= 400 && s3Err.StatusCode < 500:
+			// rejected credentials, bucket names and the like
+			// are supplied by the client
+			return ErrCodeBadRequest, http.StatusBadRequest
+		}
+	}
+
+	return ErrCodeInternal, http.StatusInternalServerError
+}
+
+// abortWithError responds with the classified status of an error.
+func (a *API) abortWithError(c *gin.Context, err error) {
+	code, status := ErrorStatus(err)
+
+	a.Log.Warn("request aborted with error",
+		zap.String("path", c.Request.URL.Path),
+		zap.Int("status", status),
+		zap.String("code", code),
+		zap.String("reason", err.Error()))
+
+	var e *Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		c.Header("Retry-After", retryAfterHeader(e.RetryAfter))
+	}
+
+	c.AbortWithStatusJSON(status, gin.H{
+		"error": err.Error(),
+		"code":  code,
+	})
+}
+
+// abortWithParseError responds to a request whose body could not be
+// read, with the classified status of errors from parseBody.
+func (a *API) abortWithParseError(c *gin.Context, err error) {
+	var e *Error
+	if errors.As(err, &e) {
+		a.abortWithError(c, err)
+		return
+	}
+
+	a.Log.Warn("unable to read post body",
+		zap.String("path", c.Request.URL.Path),
+		zap.Int("status", http.StatusBadRequest),
+		zap.String("reason", err.Error()))
+
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+		"error": "unable to read post body",
+		"code":  ErrCodeBadRequest,
+	})
+}
@@ -0,0 +1,50 @@
+package pvci
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedCoreV1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Event reasons recorded on PVCs through the PVC lifecycle.
+const (
+	EventSizeComputed       = "SizeComputed"
+	EventSourceCreated      = "SourceCreated"
+	EventInjectionStarted   = "InjectionStarted"
+	EventInjectionSucceeded = "InjectionSucceeded"
+	EventInjectionFailed    = "InjectionFailed"
+	EventCloned             = "Cloned"
+	EventDeleted            = "Deleted"
+	EventSourceLeaked       = "SourceLeaked"
+
+	EventConsumerVerifyFailed = "ConsumerVerifyFailed"
+)
+
+// newEventRecorder returns an EventRecorder writing Events
+// through the API's clientset.
+func (a *API) newEventRecorder() record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedCoreV1.EventSinkImpl{
+		Interface: a.Cs.CoreV1().Events(""),
+	})
+
+	return broadcaster.NewRecorder(scheme.Scheme, coreV1.EventSource{Component: a.Service})
+}
+
+// event records a Normal Event on an object.
+func (a *API) event(obj runtime.Object, reason string, messageFmt string, args ...interface{}) {
+	if obj == nil {
+		return
+	}
+	a.Recorder.Eventf(obj, coreV1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// warning records a Warning Event on an object.
+func (a *API) warning(obj runtime.Object, reason string, messageFmt string, args ...interface{}) {
+	if obj == nil {
+		return
+	}
+	a.Recorder.Eventf(obj, coreV1.EventTypeWarning, reason, messageFmt, args...)
+}
@@ -0,0 +1,78 @@
+package pvci
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultFastStartSize is the initial source PVC request in bytes
+// of a fast start when Config.FastStartSize is not set.
+const DefaultFastStartSize = 1 << 30
+
+// sizeResult is the outcome of a GetSize run alongside provisioning.
+type sizeResult struct {
+	objCount int64
+	size     int64
+	err      error
+}
+
+// allowsExpansion reports whether PVCs of a storage class may be
+// resized. Storage classes that can not be read are treated as
+// non-expandable.
+func (a *API) allowsExpansion(storageClass string) bool {
+	sc, err := a.getStorageClass(storageClass)
+	if err != nil {
+		a.Log.Warn("unable to get storage class, fast start disabled",
+			zap.String("storage_class", storageClass),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+}
+
+// resizePVC sets the size annotations of a fast started PVC and grows
+// its storage request to qty. PVCs can not shrink, so a qty below the
+// initial request leaves the request unchanged.
+func (a *API) resizePVC(pvc *coreV1.PersistentVolumeClaim, qty resource.Quantity, objCount int64, sz int64) (*coreV1.PersistentVolumeClaim, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				"pvci.txn2.com/requested_size": strconv.FormatInt(sz, 10),
+				"pvci.txn2.com/object_count":   strconv.FormatInt(objCount, 10),
+			},
+		},
+	}
+
+	current := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
+	if qty.Cmp(current) > 0 {
+		patch["spec"] = map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]string{
+					string(coreV1.ResourceStorage): qty.String(),
+				},
+			},
+		}
+	}
+
+	a.Log.Info("Resizing fast start PVC",
+		zap.String("name", pvc.Name),
+		zap.String("namespace", pvc.Namespace),
+		zap.String("initial_size", current.String()),
+		zap.String("size", qty.String()),
+	)
+
+	patchJson, _ := json.Marshal(patch)
+
+	return a.Cs.CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(
+		context.Background(), pvc.Name, types.MergePatchType, patchJson, metaV1.PatchOptions{},
+	)
+}
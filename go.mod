@@ -7,10 +7,11 @@ require (
 	github.com/gin-gonic/gin v1.9.0
 	github.com/imdario/mergo v0.3.9 // indirect
 	github.com/minio/minio-go/v6 v6.0.57
+	github.com/nats-io/nats.go v1.11.0
 	github.com/prometheus/client_golang v1.11.1
 	github.com/zsais/go-gin-prometheus v0.1.0
 	go.uber.org/zap v1.15.0
-	k8s.io/api v0.20.0
-	k8s.io/apimachinery v0.20.0
-	k8s.io/client-go v0.20.0
+	k8s.io/api v0.21.14
+	k8s.io/apimachinery v0.21.14
+	k8s.io/client-go v0.21.14
 )
@@ -0,0 +1,96 @@
+package pvci
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// gzipWriter compresses the body written through a gin.ResponseWriter,
+// starting on the first write so empty responses are left alone.
+type gzipWriter struct {
+	gin.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.gz == nil {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	return w.gz.Write(data)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// close flushes the compressed body, if any was written.
+func (w *gzipWriter) close() error {
+	if w.gz == nil {
+		return nil
+	}
+
+	return w.gz.Close()
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(coding, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+
+		// a zero quality value refuses the coding
+		accepted := true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(param[2:], 64)
+			accepted = err == nil && q > 0
+		}
+
+		if accepted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gzip compresses the responses of handlers returning potentially large
+// bodies for clients sending an Accept-Encoding allowing gzip.
+func (a *API) Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		err := w.close()
+		if err != nil {
+			a.Log.Warn("unable to compress response",
+				zap.String("path", c.Request.URL.Path),
+				zap.Error(err))
+		}
+		c.Writer = w.ResponseWriter
+	}
+}
@@ -0,0 +1,193 @@
+package pvci
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v6"
+)
+
+const (
+	// SizeSourceList sizes objects with a live bucket listing.
+	SizeSourceList = "list"
+
+	// SizeSourceInventory sizes objects from an S3 Inventory report.
+	SizeSourceInventory = "inventory"
+)
+
+// inventoryManifest is the manifest.json of an S3 Inventory report.
+type inventoryManifest struct {
+	DestinationBucket string `json:"destinationBucket"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// inventoryColumns holds the fileSchema positions of the inventory
+// columns read, -1 for those the report does not have.
+type inventoryColumns struct {
+	key, size, isLatest, isDeleteMarker int
+}
+
+// getSizeFromInventory gets the count and size of objects under the
+// prefix of a PVCRequestConfig from the S3 Inventory report whose
+// manifest.json is stored at S3InventoryKey in the bucket. The CSV files
+// of the report, gzipped or not, are read from its destination bucket
+// by the columns of its fileSchema. Reports including object versions
+// count only the latest versions that are not delete markers.
+func (a *API) getSizeFromInventory(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
+	objCount := int64(0)
+	totalSize := int64(0)
+
+	if pvcRequestConfig.S3InventoryKey == "" {
+		return objCount, totalSize, badRequest("s3_inventory_key is required for size_source %s", SizeSourceInventory)
+	}
+
+	manifest, err := getInventoryManifest(minioClient, pvcRequestConfig)
+	if err != nil {
+		return objCount, totalSize, err
+	}
+
+	if !strings.EqualFold(manifest.FileFormat, "CSV") {
+		return objCount, totalSize, badRequest("inventory %s has file format %s, only CSV is supported", pvcRequestConfig.S3InventoryKey, manifest.FileFormat)
+	}
+
+	cols := inventoryColumns{key: -1, size: -1, isLatest: -1, isDeleteMarker: -1}
+	for i, col := range strings.Split(manifest.FileSchema, ",") {
+		switch strings.TrimSpace(col) {
+		case "Key":
+			cols.key = i
+		case "Size":
+			cols.size = i
+		case "IsLatest":
+			cols.isLatest = i
+		case "IsDeleteMarker":
+			cols.isDeleteMarker = i
+		}
+	}
+
+	if cols.key < 0 || cols.size < 0 {
+		return objCount, totalSize, badRequest("inventory %s must have Key and Size fields", pvcRequestConfig.S3InventoryKey)
+	}
+
+	// the destination bucket is given by its ARN
+	bucket := pvcRequestConfig.S3Bucket
+	if manifest.DestinationBucket != "" {
+		bucket = manifest.DestinationBucket[strings.LastIndex(manifest.DestinationBucket, ":")+1:]
+	}
+
+	for _, file := range manifest.Files {
+		count, sz, err := sizeInventoryFile(minioClient, bucket, file.Key, cols, pvcRequestConfig.S3Prefix)
+		if err != nil {
+			return objCount, totalSize, err
+		}
+
+		objCount += count
+		totalSize += sz
+	}
+
+	return objCount, totalSize, nil
+}
+
+// getInventoryManifest reads the inventory manifest at S3InventoryKey.
+func getInventoryManifest(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig) (inventoryManifest, error) {
+	manifest := inventoryManifest{}
+
+	obj, err := minioClient.GetObject(pvcRequestConfig.S3Bucket, pvcRequestConfig.S3InventoryKey, minio.GetObjectOptions{})
+	if err != nil {
+		return manifest, err
+	}
+	defer obj.Close()
+
+	err = json.NewDecoder(obj).Decode(&manifest)
+	if err != nil {
+		if _, ok := err.(minio.ErrorResponse); ok {
+			return manifest, err
+		}
+		return manifest, badRequest("unable to read inventory manifest %s: %s", pvcRequestConfig.S3InventoryKey, err.Error())
+	}
+
+	return manifest, nil
+}
+
+// sizeInventoryFile gets the count and size of the objects under prefix
+// in a headerless inventory CSV file. Keys are URL encoded in the file.
+func sizeInventoryFile(minioClient *minio.Client, bucket string, key string, cols inventoryColumns, prefix string) (int64, int64, error) {
+	objCount := int64(0)
+	totalSize := int64(0)
+
+	obj, err := minioClient.GetObject(bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return objCount, totalSize, err
+	}
+	defer obj.Close()
+
+	var rd io.Reader = obj
+	if strings.HasSuffix(key, ".gz") {
+		gz, err := gzip.NewReader(obj)
+		if err != nil {
+			if _, ok := err.(minio.ErrorResponse); ok {
+				return objCount, totalSize, err
+			}
+			return objCount, totalSize, badRequest("unable to read inventory file %s: %s", key, err.Error())
+		}
+		defer gz.Close()
+		rd = gz
+	}
+
+	r := csv.NewReader(rd)
+	r.FieldsPerRecord = -1
+
+	line := 0
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		line += 1
+		if err != nil {
+			if _, ok := err.(minio.ErrorResponse); ok {
+				return objCount, totalSize, err
+			}
+			return objCount, totalSize, badRequest("unable to read inventory file %s line %d: %s", key, line, err.Error())
+		}
+
+		if len(record) <= cols.key || len(record) <= cols.size {
+			return objCount, totalSize, badRequest("inventory file %s line %d is missing fields", key, line)
+		}
+
+		if cols.isLatest >= 0 && cols.isLatest < len(record) && record[cols.isLatest] != "true" {
+			continue
+		}
+
+		if cols.isDeleteMarker >= 0 && cols.isDeleteMarker < len(record) && record[cols.isDeleteMarker] == "true" {
+			continue
+		}
+
+		objKey, err := url.QueryUnescape(record[cols.key])
+		if err != nil {
+			return objCount, totalSize, badRequest("inventory file %s line %d has an invalid key %s", key, line, record[cols.key])
+		}
+
+		if !strings.HasPrefix(objKey, prefix) {
+			continue
+		}
+
+		sz, err := strconv.ParseInt(strings.TrimSpace(record[cols.size]), 10, 64)
+		if err != nil || sz < 0 {
+			return objCount, totalSize, badRequest("inventory file %s line %d has an invalid size %s", key, line, record[cols.size])
+		}
+
+		objCount += 1
+		totalSize += sz
+	}
+
+	return objCount, totalSize, nil
+}
@@ -0,0 +1,108 @@
+package pvci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mcConfigDir is where the mc config Secret is mounted in the
+// injector, passed to mc as --config-dir.
+const mcConfigDir = "/mc-config"
+
+// mcConfig is the mc config.json holding the objstore alias.
+type mcConfig struct {
+	Version string                   `json:"version"`
+	Aliases map[string]mcConfigAlias `json:"aliases"`
+}
+
+// mcConfigAlias is an alias entry of an mc config.json.
+type mcConfigAlias struct {
+	URL       string `json:"url"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	API       string `json:"api"`
+	Path      string `json:"path"`
+}
+
+// createMCConfig stores an mc config.json with the objstore alias of a
+// create in a Secret mounted by the injector, returning the Secret's name.
+func (a *API) createMCConfig(pvcRequestConfig PVCRequestConfig, objStoreURL string) (string, error) {
+	api := "s3v4"
+	if pvcRequestConfig.S3SignatureVersion == SignatureV2 {
+		api = "s3v2"
+	}
+
+	key, secret := pvcRequestConfig.transferCredentials()
+	cfg, err := json.Marshal(mcConfig{
+		Version: "10",
+		Aliases: map[string]mcConfigAlias{
+			"objstore": {
+				URL:       objStoreURL,
+				AccessKey: key,
+				SecretKey: secret,
+				API:       api,
+				Path:      "auto",
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	secretName := fmt.Sprintf("%s-mc-config", pvcRequestConfig.Name)
+
+	_, err = a.Cs.CoreV1().Secrets(pvcRequestConfig.Namespace).Create(context.Background(), &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      secretName,
+			Namespace: pvcRequestConfig.Namespace,
+			Labels:    a.volLabels(pvcRequestConfig.Name),
+		},
+		Data: map[string][]byte{"config.json": cfg},
+	}, metaV1.CreateOptions{})
+
+	return secretName, err
+}
+
+// deleteMCConfig removes the mc config Secret of a create.
+func (a *API) deleteMCConfig(namespace string, secretName string) {
+	err := a.Cs.CoreV1().Secrets(namespace).Delete(context.Background(), secretName, metaV1.DeleteOptions{})
+	if err != nil {
+		a.Log.Error("unable to delete mc config",
+			zap.String("namespace", namespace),
+			zap.String("name", secretName),
+			zap.Error(err),
+		)
+	}
+}
+
+// mcConfigPod mounts the mc config Secret into the injector container
+// of a pod spec.
+func mcConfigPod(podSpec *coreV1.PodSpec, secretName string) {
+	podSpec.Volumes = append(podSpec.Volumes, coreV1.Volume{
+		Name: "mc-config",
+		VolumeSource: coreV1.VolumeSource{
+			Secret: &coreV1.SecretVolumeSource{SecretName: secretName},
+		},
+	})
+
+	container := &podSpec.InitContainers[0]
+	container.VolumeMounts = append(container.VolumeMounts, coreV1.VolumeMount{
+		MountPath: mcConfigDir,
+		Name:      "mc-config",
+		ReadOnly:  true,
+	})
+}
+
+// usesMCConfig reports whether the mc injector of a create reads its
+// credentials from an mc config Secret rather than the environment.
+// V2 signing is only configurable through the mc config.
+func (a *API) usesMCConfig(pvcRequestConfig PVCRequestConfig) bool {
+	transport := pvcRequestConfig.Transport
+	configured := a.MCConfigSecret || pvcRequestConfig.S3SignatureVersion == SignatureV2
+	return configured && (transport == "" || transport == TransportMC)
+}
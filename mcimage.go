@@ -0,0 +1,98 @@
+package pvci
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// mcReleaseLayout is the layout of mc release tags,
+// e.g. RELEASE.2020-12-10T01-26-17Z.
+const mcReleaseLayout = "RELEASE.2006-01-02T15-04-05Z"
+
+// imageTag returns the tag or digest of an image reference, empty
+// when it has neither.
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+
+	return ""
+}
+
+// parseMCRelease parses an mc release tag, or a 2006-01-02 date
+// bounding a supported range. A date bounds the end of the day when
+// endOfDay is set.
+func parseMCRelease(release string, endOfDay bool) (time.Time, error) {
+	t, err := time.Parse(mcReleaseLayout, release)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse("2006-01-02", release)
+	if err != nil {
+		return t, fmt.Errorf("%s is not an mc release tag or date", release)
+	}
+
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return t, nil
+}
+
+// checkMCImage validates the MCImage tag against the supported-version
+// policy, an allowlist of tags or a range of releases. Without a policy
+// any image is accepted.
+func (a *API) checkMCImage() error {
+	if len(a.MCImageAllowedTags) == 0 && a.MCImageMinRelease == "" && a.MCImageMaxRelease == "" {
+		return nil
+	}
+
+	tag := imageTag(a.MCImage)
+
+	for _, allowed := range a.MCImageAllowedTags {
+		if tag == allowed {
+			return nil
+		}
+	}
+
+	if a.MCImageMinRelease == "" && a.MCImageMaxRelease == "" {
+		return fmt.Errorf("mc image %s is not one of the allowed tags %s",
+			a.MCImage, strings.Join(a.MCImageAllowedTags, ", "))
+	}
+
+	release, err := time.Parse(mcReleaseLayout, tag)
+	if err != nil {
+		return fmt.Errorf("mc image %s must be tagged with a release (%s) to check its supported range",
+			a.MCImage, mcReleaseLayout)
+	}
+
+	if a.MCImageMinRelease != "" {
+		min, err := parseMCRelease(a.MCImageMinRelease, false)
+		if err != nil {
+			return err
+		}
+		if release.Before(min) {
+			return fmt.Errorf("mc image %s is older than the minimum supported release %s",
+				a.MCImage, a.MCImageMinRelease)
+		}
+	}
+
+	if a.MCImageMaxRelease != "" {
+		max, err := parseMCRelease(a.MCImageMaxRelease, true)
+		if err != nil {
+			return err
+		}
+		if release.After(max) {
+			return fmt.Errorf("mc image %s is newer than the maximum supported release %s",
+				a.MCImage, a.MCImageMaxRelease)
+		}
+	}
+
+	return nil
+}
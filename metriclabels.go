@@ -0,0 +1,142 @@
+package pvci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DefaultMetricLabelMaxValues is the default number of distinct values
+// a metric label may take.
+const DefaultMetricLabelMaxValues = 20
+
+// metricLabelName matches valid Prometheus label names.
+var metricLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedMetricLabels are the labels of create metrics set by PVCI.
+var reservedMetricLabels = map[string]bool{
+	"job":       true,
+	"instance":  true,
+	"namespace": true,
+	"pvc":       true,
+	"outcome":   true,
+}
+
+// metricLabelValues tracks the distinct values admitted for each metric
+// label, bounding the series a label can create.
+type metricLabelValues struct {
+	mu     sync.Mutex
+	max    int
+	values map[string]map[string]bool
+}
+
+func newMetricLabelValues(max int) *metricLabelValues {
+	return &metricLabelValues{max: max, values: make(map[string]map[string]bool)}
+}
+
+// admit records a value of a metric label, reporting false when the
+// label already has its maximum of other values.
+func (m *metricLabelValues) admit(key string, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.values[key] == nil {
+		m.values[key] = make(map[string]bool)
+	}
+
+	if !m.values[key][value] && len(m.values[key]) >= m.max {
+		return false
+	}
+	m.values[key][value] = true
+
+	return true
+}
+
+// admitted reports whether a value of a metric label was admitted.
+func (m *metricLabelValues) admitted(key string, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.values[key][value]
+}
+
+// checkMetricLabelNames validates the configured MetricLabels as
+// Prometheus label names not set by PVCI.
+func checkMetricLabelNames(names []string) error {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if !metricLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid metric label %s", name)
+		}
+		if reservedMetricLabels[name] {
+			return fmt.Errorf("metric label %s is reserved", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("metric label %s is duplicated", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// checkMetricLabels validates the metric labels of a PVCRequestConfig.
+// Only the configured MetricLabels may be set, and a value beyond the
+// MetricLabelMaxValues distinct values of a label is rejected rather
+// than creating another series.
+func (a *API) checkMetricLabels(pvcRequestConfig PVCRequestConfig) error {
+	allowed := make(map[string]bool)
+	for _, name := range a.MetricLabels {
+		allowed[name] = true
+	}
+
+	for k, v := range pvcRequestConfig.MetricLabels {
+		if !allowed[k] {
+			return badRequest("metric label %s is not configured", k)
+		}
+
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return badRequest("invalid metric label value %s: %s", v, strings.Join(errs, ", "))
+		}
+
+		if !a.metricLabelValues.admit(k, v) {
+			return badRequest("metric label %s has reached its limit of %d values", k, a.MetricLabelMaxValues)
+		}
+	}
+
+	return nil
+}
+
+// metricLabels returns the configured MetricLabels with their admitted
+// values from a request, empty for those unset or not admitted.
+func (a *API) metricLabels(requested map[string]string) prometheus.Labels {
+	labels := prometheus.Labels{}
+	for _, name := range a.MetricLabels {
+		labels[name] = ""
+		if v, ok := requested[name]; ok && a.metricLabelValues.admitted(name, v) {
+			labels[name] = v
+		}
+	}
+
+	return labels
+}
+
+// countCreate counts a completed create Operation by namespace, outcome
+// and metric labels.
+func (a *API) countCreate(op Operation, requested map[string]string) {
+	labels := a.metricLabels(requested)
+	labels["namespace"] = op.Namespace
+	labels["outcome"] = op.Status
+
+	a.createsTotal.With(labels).Inc()
+}
+
+// Collector returns the collector of the create metrics labelled by
+// the configured MetricLabels, for registration by the caller.
+func (a *API) Collector() prometheus.Collector {
+	return a.createsTotal
+}
@@ -0,0 +1,58 @@
+package pvci
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// pvcBindDuration measures the time from requesting a PVC until it
+	// reaches the Bound phase, labelled by the PVC's role (src or final).
+	pvcBindDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pvci_pvc_bind_duration_seconds",
+		Help:    "Time for a PVCI created PVC to reach the Bound phase.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"storage_class", "pvc"})
+
+	// jobRunDuration measures the time from creating an injector Job
+	// until it succeeds or fails.
+	jobRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pvci_job_run_duration_seconds",
+		Help:    "Time for a PVCI injector Job to run to completion.",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 12),
+	}, []string{"storage_class"})
+
+	// injectorTTFB measures the time from creating an injector Job
+	// until its source volume usage first grows, separating startup
+	// overhead (scheduling, image pulls, auth) from transfer speed.
+	injectorTTFB = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pvci_injector_ttfb_seconds",
+		Help:    "Time for a PVCI injector Job to write its first data to the source PVC.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"storage_class"})
+
+	// createQueueDepth counts async creates waiting for a worker.
+	createQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pvci_create_queue_depth",
+		Help: "Number of async creates waiting for a worker.",
+	})
+
+	// createPanics counts creates ended by a recovered panic.
+	createPanics = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pvci_create_panics_total",
+		Help: "Number of PVCI creates ended by a recovered panic.",
+	})
+
+	// createsInFlight counts creates running, labelled by namespace.
+	createsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pvci_creates_in_flight",
+		Help: "Number of PVCI creates running.",
+	}, []string{"namespace"})
+
+	// bytesInFlight sums the bucket size of sized creates running,
+	// labelled by namespace.
+	bytesInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pvci_bytes_in_flight",
+		Help: "Bytes of object data being injected by running PVCI creates.",
+	}, []string{"namespace"})
+)
@@ -0,0 +1,67 @@
+// Package natspub publishes PVCI lifecycle events to NATS with the
+// nats.go client. It is kept apart from package pvci, which only
+// defines the Publisher interface, so embedding PVCI does not depend on
+// a NATS client.
+package natspub
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultTimeout bounds connecting to the server and flushing each
+// publish when no timeout is set.
+const DefaultTimeout = 10 * time.Second
+
+// Publisher is a pvci.Publisher holding a NATS connection, which the
+// client reconnects while the server is unavailable.
+type Publisher struct {
+	Timeout time.Duration
+
+	conn *nats.Conn
+}
+
+// New connects a Publisher to a nats://[user:pass@|token@]host:port URL,
+// or a tls:// URL for servers requiring TLS. A server unavailable at
+// startup is connected to in the background.
+func New(natsURL string) (*Publisher, error) {
+	u, err := url.Parse(natsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+		return nil, fmt.Errorf("NATS URL must be nats://host:port or tls://host:port, got %s", u.Redacted())
+	}
+
+	conn, err := nats.Connect(natsURL,
+		nats.Name("pvci"),
+		nats.Timeout(DefaultTimeout),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher{Timeout: DefaultTimeout, conn: conn}, nil
+}
+
+// Publish sends data to a subject, waiting for the server to receive
+// it.
+func (p *Publisher) Publish(subject string, data []byte) error {
+	err := p.conn.Publish(subject, data)
+	if err != nil {
+		return err
+	}
+
+	return p.conn.FlushTimeout(p.Timeout)
+}
+
+// Close closes the connection of the Publisher.
+func (p *Publisher) Close() {
+	p.conn.Close()
+}
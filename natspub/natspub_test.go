@@ -0,0 +1,69 @@
+package natspub
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestPublisher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	defer ln.Close()
+
+	// a NATS server answering pings and recording what it receives
+	received := make(chan string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n"))
+
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(line, "PING") {
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+			received <- line
+		}
+	}()
+
+	p, err := New("nats://s3cr3t@" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	defer p.Close()
+
+	err = p.Publish("pvci.create", []byte(`{"name":"vol"}`))
+	if err != nil {
+		t.Fatalf("Publish: %s", err)
+	}
+
+	lines := make([]string, 0)
+	for len(received) > 0 {
+		lines = append(lines, <-received)
+	}
+	msg := strings.Join(lines, "")
+
+	if !strings.Contains(msg, `"auth_token":"s3cr3t"`) {
+		t.Errorf("expected token authentication, got %s", msg)
+	}
+
+	if !strings.Contains(msg, "PUB pvci.create 14\r\n{\"name\":\"vol\"}\r\n") {
+		t.Errorf("unexpected publish %q", msg)
+	}
+
+	if _, err := New("http://localhost:4222"); err == nil {
+		t.Error("expected error for a non nats:// URL")
+	}
+}
@@ -0,0 +1,50 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reasonOOMKilled is the termination reason of a container killed
+// for exceeding its memory limit.
+const reasonOOMKilled = "OOMKilled"
+
+// jobFailure returns the error of a failed Job, an INJECTOR_OOM Error
+// when a container of one of its pods was OOMKilled.
+func (a *API) jobFailure(namespace string, jobName string) error {
+	pods, err := a.Cs.CoreV1().Pods(namespace).List(context.Background(), metaV1.ListOptions{
+		LabelSelector: "job-name=" + jobName,
+	})
+	if err != nil {
+		return fmt.Errorf("job failed")
+	}
+
+	for _, pod := range pods.Items {
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if oomKilled(cs) {
+				return newError(ErrCodeInjectorOOM, http.StatusInternalServerError,
+					"job failed, container %s of pod %s was OOMKilled, raise the memory limit of injector pods",
+					cs.Name, pod.Name)
+			}
+		}
+	}
+
+	return fmt.Errorf("job failed")
+}
+
+// oomKilled reports whether a container's current or last termination
+// was an OOM kill.
+func oomKilled(cs coreV1.ContainerStatus) bool {
+	for _, terminated := range []*coreV1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+		if terminated != nil && terminated.Reason == reasonOOMKilled {
+			return true
+		}
+	}
+
+	return false
+}
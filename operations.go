@@ -0,0 +1,170 @@
+package pvci
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultOperationRetention is how long completed operations are
+// listed by /operations when Config.OperationRetention is zero.
+const DefaultOperationRetention = 24 * time.Hour
+
+// Operation statuses.
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+
+	// OperationCancelled is reserved for creates stopped before
+	// completing, PVCI does not cancel creates yet.
+	OperationCancelled = "cancelled"
+)
+
+// Operation is a create tracked by the PVCI replica running it.
+// Objects and Bytes are the bucket objects and size being injected,
+// once sized.
+type Operation struct {
+	ID        int64      `json:"id"`
+	Operation string     `json:"operation"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Started   time.Time  `json:"started"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Objects   int64      `json:"objects"`
+	Bytes     int64      `json:"bytes"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// operations is a concurrency safe in-memory history of operations,
+// dropping completed operations after the retention.
+type operations struct {
+	mu        sync.Mutex
+	nextID    int64
+	ops       []*Operation
+	running   map[string]*Operation
+	retention time.Duration
+}
+
+func newOperations(retention time.Duration) *operations {
+	return &operations{running: make(map[string]*Operation), retention: retention}
+}
+
+// start records a running operation on a volume.
+func (o *operations) start(operation string, namespace string, name string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.nextID += 1
+	op := &Operation{
+		ID:        o.nextID,
+		Operation: operation,
+		Namespace: namespace,
+		Name:      name,
+		Status:    OperationRunning,
+		Started:   time.Now().UTC(),
+	}
+
+	o.ops = append(o.ops, op)
+	o.running[namespace+"/"+name] = op
+
+	return op
+}
+
+// setSize sets the objects and bytes of the running operation on a
+// volume.
+func (o *operations) setSize(namespace string, name string, objects int64, bytes int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if op, ok := o.running[namespace+"/"+name]; ok {
+		op.Objects = objects
+		op.Bytes = bytes
+	}
+}
+
+// finish completes an operation with the result of err, returning a
+// copy of the completed operation.
+func (o *operations) finish(op *Operation, err error) Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	finished := time.Now().UTC()
+	op.Finished = &finished
+	op.Status = OperationSucceeded
+	if err != nil {
+		op.Status = OperationFailed
+		op.Error = err.Error()
+	}
+
+	key := op.Namespace + "/" + op.Name
+	if o.running[key] == op {
+		delete(o.running, key)
+	}
+
+	return *op
+}
+
+// list returns copies of the operations matching a namespace and
+// status, newest first. Empty filters match every operation.
+func (o *operations) list(namespace string, status string) []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.expire()
+
+	ops := make([]Operation, 0)
+	for _, op := range o.ops {
+		if namespace != "" && op.Namespace != namespace {
+			continue
+		}
+		if status != "" && op.Status != status {
+			continue
+		}
+		ops = append(ops, *op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].ID > ops[j].ID
+	})
+
+	return ops
+}
+
+// expire drops operations completed before the retention, the caller
+// holds the lock.
+func (o *operations) expire() {
+	cutoff := time.Now().Add(-o.retention)
+
+	kept := o.ops[:0]
+	for _, op := range o.ops {
+		if op.Finished != nil && op.Finished.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, op)
+	}
+
+	o.ops = kept
+}
+
+// OperationsHandler used by the HTTP GET /operations endpoint lists
+// the running and completed operations of this PVCI replica,
+// optionally filtered by the namespace and status query parameters.
+func (a *API) OperationsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+
+		switch status {
+		case "", OperationRunning, OperationSucceeded, OperationFailed, OperationCancelled:
+		default:
+			a.abortWithError(c, badRequest("unknown operation status %s", status))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"operations": a.operations.list(c.Query("namespace"), status)})
+	}
+}
@@ -0,0 +1,281 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/minio/minio-go/v6"
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxPartitionPlanSize is the largest copy plan stored for a partitioned
+// create, leaving headroom under the 1MiB ConfigMap limit.
+const MaxPartitionPlanSize = 900 * 1024
+
+// UnpartitionedDir receives objects whose keys do not match a
+// PartitionBy rule.
+const UnpartitionedDir = "_unpartitioned"
+
+// partitionScript copies each object of the plan, a tab separated key
+// and destination per line, into its destination on the source PVC.
+// With PVCI_SHARDS, only every PVCI_SHARDS-th line starting at the
+// JOB_COMPLETION_INDEX of an Indexed Job pod is copied.
+const partitionScript = `set -e
+shards="${PVCI_SHARDS:-1}"
+if [ "$shards" -gt 1 ] && [ -z "$JOB_COMPLETION_INDEX" ]; then
+  echo "sharded copy requires an Indexed Job" >&2
+  exit 1
+fi
+index="${JOB_COMPLETION_INDEX:-0}"
+tab="$(printf '\t')"
+line=0
+while IFS="$tab" read -r key dest; do
+  if [ $((line % shards)) -eq "$index" ]; then
+    mkdir -p "$(dirname "/srcpvc/$dest")"
+    mc cp $PVCI_MC_FLAGS "objstore/$PVCI_BUCKET/$key" "/srcpvc/$dest"
+  fi
+  line=$((line + 1))
+done < /plan/files
+`
+
+// PartitionBy routes each object into a subdirectory of the PVC derived
+// from its key, relative to the S3Prefix. Pattern is a regular expression
+// whose first capture group (or whole match) names the subdirectory,
+// while Segment selects a "/" separated segment of the key by index.
+// Objects keep their path under the subdirectory; keys not matching
+// land in UnpartitionedDir.
+type PartitionBy struct {
+	Pattern string `json:"pattern"`
+	Segment *int   `json:"segment"`
+}
+
+// partitioner derives the subdirectory of an object key.
+type partitioner func(key string) string
+
+// newPartitioner validates a PartitionBy rule and returns its partitioner.
+func newPartitioner(rule *PartitionBy) (partitioner, error) {
+	switch {
+	case rule.Pattern != "" && rule.Segment != nil:
+		return nil, badRequest("partition_by takes a pattern or a segment, not both")
+	case rule.Pattern != "":
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, badRequest("partition_by pattern is invalid: %s", err.Error())
+		}
+
+		return func(key string) string {
+			m := re.FindStringSubmatch(key)
+			if len(m) > 1 {
+				return m[1]
+			}
+			if len(m) == 1 {
+				return m[0]
+			}
+			return ""
+		}, nil
+	case rule.Segment != nil:
+		if *rule.Segment < 0 {
+			return nil, badRequest("partition_by segment must not be negative")
+		}
+
+		return func(key string) string {
+			segments := strings.Split(key, "/")
+			if *rule.Segment < len(segments)-1 {
+				return segments[*rule.Segment]
+			}
+			return ""
+		}, nil
+	}
+
+	return nil, badRequest("partition_by requires a pattern or a segment")
+}
+
+// usesPlan reports whether the objects of a PVCRequestConfig are
+// copied through a copy plan, to partition them, to map a key
+// delimiter other than "/" to directories, to copy them in order, to
+// copy only those of selected content types or to shard them. Plans
+// list objects in the sorted key order of S3 listings and are copied
+// one object at a time.
+func usesPlan(pvcRequestConfig PVCRequestConfig) bool {
+	return pvcRequestConfig.PartitionBy != nil || pvcRequestConfig.delimiter() != "/" || pvcRequestConfig.Sorted ||
+		len(pvcRequestConfig.ContentTypes) > 0 || pvcRequestConfig.Shards > 1
+}
+
+// checkPartitionBy validates the PartitionBy rule and key delimiter of
+// a PVCRequestConfig.
+func checkPartitionBy(pvcRequestConfig PVCRequestConfig) error {
+	if !usesPlan(pvcRequestConfig) {
+		return nil
+	}
+
+	option := "sorted"
+	switch {
+	case pvcRequestConfig.PartitionBy != nil:
+		option = "partition_by"
+	case pvcRequestConfig.delimiter() != "/":
+		option = "s3_delimiter"
+	case len(pvcRequestConfig.ContentTypes) > 0:
+		option = "content_types"
+	case pvcRequestConfig.Shards > 1:
+		option = "shards"
+	}
+
+	if pvcRequestConfig.Transport != "" && pvcRequestConfig.Transport != TransportMC {
+		return badRequest("%s is only supported by the %s transport", option, TransportMC)
+	}
+
+	if len(pvcRequestConfig.Command) > 0 {
+		return badRequest("%s can not be combined with a custom command", option)
+	}
+
+	if strings.ContainsAny(pvcRequestConfig.S3Delimiter, "\t\n") {
+		return badRequest("s3_delimiter must not contain tabs or newlines")
+	}
+
+	if pvcRequestConfig.PartitionBy == nil {
+		return nil
+	}
+
+	_, err := newPartitioner(pvcRequestConfig.PartitionBy)
+
+	return err
+}
+
+// keyPath maps the directories of a key separated by delimiter
+// to a filesystem path.
+func keyPath(key string, delimiter string) string {
+	if delimiter == "/" {
+		return key
+	}
+
+	return strings.ReplaceAll(key, delimiter, "/")
+}
+
+// partitionPlan lists the objects of a PVCRequestConfig, returning a
+// copy plan line of key and destination for each. Without a PartitionBy
+// rule, objects land under the last directory of the prefix, as with
+// mc cp -r.
+func (a *API) partitionPlan(pvcRequestConfig PVCRequestConfig) (string, error) {
+	var partition partitioner
+	if pvcRequestConfig.PartitionBy != nil {
+		var err error
+		partition, err = newPartitioner(pvcRequestConfig.PartitionBy)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	delimiter := pvcRequestConfig.delimiter()
+
+	prefixDir := strings.Trim(keyPath(pvcRequestConfig.S3Prefix, delimiter), "/")
+	if prefixDir != "" {
+		prefixDir = path.Base(prefixDir)
+	}
+
+	minioClient, err := a.getMinIOClient(pvcRequestConfig)
+	if err != nil {
+		return "", err
+	}
+
+	plan := strings.Builder{}
+	err = a.listObjects(minioClient, pvcRequestConfig, func(object minio.ObjectInfo) error {
+		if strings.ContainsAny(object.Key, "\t\n") {
+			return badRequest("object key %q can not be partitioned", object.Key)
+		}
+
+		rel := strings.TrimPrefix(object.Key, pvcRequestConfig.S3Prefix)
+		rel = strings.TrimPrefix(keyPath(strings.TrimPrefix(rel, delimiter), delimiter), "/")
+
+		dir := prefixDir
+		if partition != nil {
+			dir = partition(rel)
+			if dir == "" {
+				dir = UnpartitionedDir
+			}
+		}
+
+		plan.WriteString(object.Key + "\t" + path.Join(dir, rel) + "\n")
+		if plan.Len() > MaxPartitionPlanSize {
+			return badRequest("partition plan exceeds %d bytes, too many objects to partition", MaxPartitionPlanSize)
+		}
+
+		return nil
+	})
+
+	return plan.String(), err
+}
+
+// createPartitionPlan stores the copy plan of a partitioned create in a
+// ConfigMap mounted by the injector, returning the ConfigMap's name.
+func (a *API) createPartitionPlan(pvcRequestConfig PVCRequestConfig) (string, error) {
+	plan, err := a.partitionPlan(pvcRequestConfig)
+	if err != nil {
+		return "", err
+	}
+
+	planName := fmt.Sprintf("%s-plan", pvcRequestConfig.Name)
+
+	_, err = a.Cs.CoreV1().ConfigMaps(pvcRequestConfig.Namespace).Create(context.Background(), &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      planName,
+			Namespace: pvcRequestConfig.Namespace,
+			Labels:    a.volLabels(pvcRequestConfig.Name),
+		},
+		Data: map[string]string{"files": plan},
+	}, metaV1.CreateOptions{})
+
+	return planName, err
+}
+
+// deletePartitionPlan removes the copy plan ConfigMap of a create.
+func (a *API) deletePartitionPlan(namespace string, planName string) {
+	err := a.Cs.CoreV1().ConfigMaps(namespace).Delete(context.Background(), planName, metaV1.DeleteOptions{})
+	if err != nil {
+		a.Log.Error("unable to delete partition plan",
+			zap.String("namespace", namespace),
+			zap.String("name", planName),
+			zap.Error(err),
+		)
+	}
+}
+
+// partitionPod runs the partition script in the mc injector container
+// of a pod spec, mounting the copy plan ConfigMap.
+func (a *API) partitionPod(podSpec *coreV1.PodSpec, pvcRequestConfig PVCRequestConfig, planName string) {
+	podSpec.Volumes = append(podSpec.Volumes, coreV1.Volume{
+		Name: "plan",
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{
+				LocalObjectReference: coreV1.LocalObjectReference{Name: planName},
+			},
+		},
+	})
+
+	mcFlags := make([]string, 0)
+	if pvcRequestConfig.PreserveMetadata {
+		mcFlags = append(mcFlags, "--preserve")
+	}
+	if pvcRequestConfig.S3AsOf != "" {
+		mcFlags = append(mcFlags, "--rewind", pvcRequestConfig.S3AsOf)
+	}
+	if a.usesMCConfig(pvcRequestConfig) {
+		mcFlags = append(mcFlags, "--config-dir", mcConfigDir)
+	}
+
+	container := &podSpec.InitContainers[0]
+	container.Command = []string{"sh", "-c", partitionScript}
+	container.Env = append(container.Env,
+		coreV1.EnvVar{Name: "PVCI_BUCKET", Value: pvcRequestConfig.S3Bucket},
+		coreV1.EnvVar{Name: "PVCI_MC_FLAGS", Value: strings.Join(mcFlags, " ")},
+	)
+	container.VolumeMounts = append(container.VolumeMounts, coreV1.VolumeMount{
+		MountPath: "/plan",
+		Name:      "plan",
+		ReadOnly:  true,
+	})
+}
@@ -0,0 +1,72 @@
+package pvci
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// createPool runs queued creates on a bounded number of workers.
+type createPool struct {
+	queue chan func()
+	once  sync.Once
+	size  int
+	log   *zap.Logger
+}
+
+// newCreatePool returns a pool of size workers accepting up to
+// queueSize waiting creates. A size of zero leaves the pool unbounded.
+func newCreatePool(size int, queueSize int, log *zap.Logger) *createPool {
+	return &createPool{
+		queue: make(chan func(), queueSize),
+		size:  size,
+		log:   log,
+	}
+}
+
+// submit runs fn on a pool worker, returning an UNAVAILABLE Error
+// when the queue is full.
+func (p *createPool) submit(fn func()) error {
+	if p.size < 1 {
+		go p.run(fn)
+		return nil
+	}
+
+	p.once.Do(func() {
+		for i := 0; i < p.size; i++ {
+			go p.work()
+		}
+	})
+
+	select {
+	case p.queue <- fn:
+		createQueueDepth.Inc()
+		return nil
+	default:
+		return unavailable("create queue is full (%d waiting)", cap(p.queue))
+	}
+}
+
+// work runs queued creates until the queue is closed.
+func (p *createPool) work() {
+	for fn := range p.queue {
+		createQueueDepth.Dec()
+		p.run(fn)
+	}
+}
+
+// run runs a create, recovering a panic so it takes down neither the
+// worker nor the process.
+func (p *createPool) run(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			createPanics.Inc()
+			p.log.Error("recovered panic in create worker",
+				zap.Any("panic", r),
+				zap.Stack("stack"),
+			)
+		}
+	}()
+
+	fn()
+}
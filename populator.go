@@ -0,0 +1,185 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventPopulated is recorded on a PVC filled by a volume populator.
+const EventPopulated = "Populated"
+
+// Event reasons of a failing volume populator, see
+// lib-volume-populator.
+const (
+	PopulatorCreationError    = "PopulatorCreationError"
+	PopulatorFailed           = "PopulatorFailed"
+	PopulatorPVCCreationError = "PopulatorPVCCreationError"
+)
+
+// populatorFailureReasons are the event reasons failing a populated
+// create before its timeout.
+var populatorFailureReasons = map[string]bool{
+	PopulatorCreationError:    true,
+	PopulatorFailed:           true,
+	PopulatorPVCCreationError: true,
+}
+
+// PopulatorRef references a volume populator custom resource in the
+// namespace of a create, such as one describing the bucket to pull.
+// Populators require the AnyVolumeDataSource feature gate.
+type PopulatorRef struct {
+	APIGroup string `json:"api_group"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+}
+
+// checkPopulator validates the PopulatorRef of a VolConfig.
+func checkPopulator(volConfig VolConfig) error {
+	populator := volConfig.Populator
+	if populator == nil {
+		return nil
+	}
+
+	if populator.APIGroup == "" || populator.Kind == "" || populator.Name == "" {
+		return badRequest("populator requires an api_group, kind and name")
+	}
+
+	if volConfig.FastStart || volConfig.DebugKeepSource || volConfig.FinalStorageClass != "" {
+		return badRequest("populator can not be combined with fast_start, debug_keep_source or final_storage_class")
+	}
+
+	return nil
+}
+
+// createPopulatedPVC creates the final PVC of a create with its
+// PopulatorRef as data source, leaving the populator to pull the data
+// in place of a source PVC and injector Job.
+func (a *API) createPopulatedPVC(pvcRequestConfig PVCRequestConfig, objCount int64, sz int64, storageQty resource.Quantity, annotations map[string]string) error {
+	populator := pvcRequestConfig.Populator
+	storageClass := pvcRequestConfig.StorageClass
+	volMode := coreV1.PersistentVolumeFilesystem
+
+	annotations["pvci.txn2.com/populator"] = populator.APIGroup + "/" + populator.Kind + "/" + populator.Name
+	stampRequest(annotations, pvcRequestConfig)
+
+	pvc, err := a.Cs.CoreV1().PersistentVolumeClaims(pvcRequestConfig.Namespace).Create(context.Background(), &coreV1.PersistentVolumeClaim{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        pvcRequestConfig.Name,
+			Namespace:   pvcRequestConfig.Namespace,
+			Labels:      a.volLabels(pvcRequestConfig.Name),
+			Annotations: annotations,
+		},
+		Spec: coreV1.PersistentVolumeClaimSpec{
+			DataSource: &coreV1.TypedLocalObjectReference{
+				APIGroup: &populator.APIGroup,
+				Kind:     populator.Kind,
+				Name:     populator.Name,
+			},
+			AccessModes: []coreV1.PersistentVolumeAccessMode{
+				pvcRequestConfig.finalAccessMode(),
+			},
+			StorageClassName: &storageClass,
+			VolumeMode:       &volMode,
+			Resources: coreV1.ResourceRequirements{
+				Requests: coreV1.ResourceList{
+					coreV1.ResourceStorage: storageQty,
+				},
+			},
+		},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	a.Log.Info("Created populated PVC",
+		zap.String("name", pvcRequestConfig.Name),
+		zap.String("namespace", pvcRequestConfig.Namespace),
+		zap.String("populator", annotations["pvci.txn2.com/populator"]),
+		zap.String("requested_size", annotations["pvci.txn2.com/requested_size"]),
+	)
+
+	// the PVC binds once the populator has filled its volume, given
+	// the time an injector Job would be allotted for the same data
+	runEst := a.transferEstimate(pvcRequestConfig, a.copyStrategy(pvcRequestConfig, objCount), objCount, sz)
+	err = a.waitPopulated(pvc, storageClass, jobTimeout(runEst))
+	if err != nil {
+		a.warning(pvc, EventInjectionFailed, "Populator %s did not populate the PVC: %s", populator.Name, err.Error())
+		if a.CleanupOnFailure {
+			a.cleanupFinal(pvcRequestConfig.Namespace, pvcRequestConfig.Name, pvcRequestConfig.Name+"-src")
+		}
+		return err
+	}
+
+	a.event(pvc, EventPopulated, "Populated by %s %s", populator.Kind, strconv.Quote(populator.Name))
+
+	return nil
+}
+
+// waitPopulated waits up to timeout seconds for a populator to fill and
+// bind a PVC, polling every JobAttemptInterval. Provisioning events are
+// logged without failing the wait, which fails early only on an event
+// of the populator itself failing.
+func (a *API) waitPopulated(pvc *coreV1.PersistentVolumeClaim, storageClass string, timeout int64) error {
+	start := time.Now()
+	waitsForConsumer := a.waitsForFirstConsumer(storageClass)
+
+	for {
+		time.Sleep(time.Duration(JobAttemptInterval) * time.Second)
+
+		current, err := a.getPVC(pvc.Namespace, pvc.Name)
+		if err != nil {
+			return err
+		}
+
+		if current.Status.Phase == coreV1.ClaimBound {
+			pvcBindDuration.WithLabelValues(storageClass, "final").Observe(time.Since(start).Seconds())
+			return nil
+		}
+
+		failure, err := a.pvcEvent(current, populatorFailureReasons)
+		if err != nil {
+			a.Log.Warn("unable to get PVC populator events",
+				zap.String("name", pvc.Name),
+				zap.String("namespace", pvc.Namespace),
+				zap.Error(err))
+		}
+
+		if failure != nil {
+			return fmt.Errorf("populator failed to populate PVC %s: %s", pvc.Name, failure.Message)
+		}
+
+		event, _ := a.provisioningEvent(current)
+
+		reason, message := "", ""
+		if event != nil {
+			reason, message = event.Reason, event.Message
+		}
+
+		a.Log.Info("PVC waits for its populator",
+			zap.String("name", pvc.Name),
+			zap.String("namespace", pvc.Namespace),
+			zap.Any("status", current.Status.Phase),
+			zap.String("reason", reason),
+			zap.String("message", message))
+
+		// the populator fills the PVC once its first consumer is scheduled
+		if waitsForConsumer && reason == WaitForFirstConsumer {
+			return nil
+		}
+
+		if time.Since(start) > time.Duration(timeout)*time.Second {
+			a.Log.Error("populated PVC is unable to reach Bound phase in allotted time",
+				zap.String("name", pvc.Name),
+				zap.String("namespace", pvc.Namespace),
+				zap.Int64("timeout_seconds", timeout))
+			return fmt.Errorf("populated PVC %s is unable to reach Bound phase in %d seconds", pvc.Name, timeout)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PrepullTimeout is the number of seconds a pre-pull DaemonSet is given
+// to become ready on its nodes before it is removed.
+const PrepullTimeout = 600
+
+// PrepullInterval is the number of seconds between checks of a
+// pre-pull DaemonSet.
+const PrepullInterval = 5
+
+// PrepullRequest structures the body of the /prepull endpoint. The
+// images of Transports (default TransportMC) and the verify image are
+// pulled on the nodes matching NodeSelector, or every schedulable node.
+// The DaemonSet runs in Namespace, or the SystemNamespace when empty.
+type PrepullRequest struct {
+	Namespace    string            `json:"namespace"`
+	Transports   []string          `json:"transports"`
+	NodeSelector map[string]string `json:"node_selector"`
+}
+
+// PrepullHandler used by the HTTP POST /prepull endpoint starts a
+// pre-pull of the injector images, returning the DaemonSet pulling them.
+func (a *API) PrepullHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		prepullRequest := &PrepullRequest{}
+		err := a.parseBody(c, prepullRequest)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		ds, err := a.Prepull(*prepullRequest)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		images := make([]string, 0)
+		for _, container := range ds.Spec.Template.Spec.InitContainers {
+			images = append(images, container.Image)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"namespace": ds.Namespace, "name": ds.Name, "images": images})
+	}
+}
+
+// Prepull creates a DaemonSet whose init containers run the injector
+// images on each node, warming their image caches ahead of the first
+// create. The DaemonSet is removed once ready on every node, or after
+// PrepullTimeout seconds. A pre-pull already running in the namespace
+// is a conflict.
+func (a *API) Prepull(prepullRequest PrepullRequest) (*appsV1.DaemonSet, error) {
+	ctx := context.Background()
+
+	cfg := PVCRequestConfig{}
+	cfg.Namespace = a.SystemNamespace
+	if prepullRequest.Namespace != "" {
+		cfg.Namespace = prepullRequest.Namespace
+		err := a.resolveNamespace(&cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(prepullRequest.Transports) == 0 {
+		prepullRequest.Transports = []string{TransportMC}
+	}
+
+	initContainers := make([]coreV1.Container, 0)
+	for _, transport := range prepullRequest.Transports {
+		container, err := a.prepullContainer(transport)
+		if err != nil {
+			return nil, err
+		}
+		initContainers = append(initContainers, container)
+	}
+
+	labels := map[string]string{
+		a.labelKey("service"): a.Service,
+		a.labelKey("version"): a.Version,
+		a.labelKey("job"):     "prepull",
+	}
+
+	ds, err := a.Cs.AppsV1().DaemonSets(cfg.Namespace).Create(ctx, &appsV1.DaemonSet{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-prepull", a.Service),
+			Namespace: cfg.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsV1.DaemonSetSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: labels},
+			Template: coreV1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{
+					Labels:      labels,
+					Annotations: a.InjectorAnnotations,
+				},
+				Spec: coreV1.PodSpec{
+					NodeSelector:   prepullRequest.NodeSelector,
+					InitContainers: initContainers,
+					Containers: []coreV1.Container{
+						{
+							Name:    "verify",
+							Image:   a.VerifyImage,
+							Command: []string{"sh", "-c", fmt.Sprintf("sleep %d", PrepullTimeout)},
+						},
+					},
+				},
+			},
+		},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	go a.removePrepull(ds.Namespace, ds.Name)
+
+	return ds, nil
+}
+
+// prepullContainer returns a container running the image of a
+// transport to completion.
+func (a *API) prepullContainer(transport string) (coreV1.Container, error) {
+	switch transport {
+	case TransportMC:
+		return coreV1.Container{Name: TransportMC, Image: a.MCImage, Command: []string{"mc", "--version"}}, nil
+	case TransportRclone:
+		return coreV1.Container{Name: TransportRclone, Image: a.RcloneImage, Command: []string{"rclone", "version"}}, nil
+	case TransportAWSCLI:
+		return coreV1.Container{Name: TransportAWSCLI, Image: a.AWSCLIImage, Command: []string{"aws", "--version"}}, nil
+	}
+
+	return coreV1.Container{}, badRequest("unknown transport %s", transport)
+}
+
+// removePrepull waits for a pre-pull DaemonSet to be ready on every
+// node it schedules to, up to PrepullTimeout seconds, then deletes it.
+func (a *API) removePrepull(namespace string, name string) {
+	ctx := context.Background()
+	dsClient := a.Cs.AppsV1().DaemonSets(namespace)
+
+	ready := false
+	for i := 0; i < PrepullTimeout/PrepullInterval && !ready; i++ {
+		time.Sleep(PrepullInterval * time.Second)
+
+		ds, err := dsClient.Get(ctx, name, metaV1.GetOptions{})
+		if err != nil {
+			a.Log.Warn("unable to get prepull daemonset",
+				zap.String("namespace", namespace),
+				zap.String("name", name),
+				zap.Error(err))
+			continue
+		}
+
+		ready = ds.Status.DesiredNumberScheduled > 0 && ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled
+	}
+
+	if !ready {
+		a.Log.Warn("prepull daemonset not ready on every node, removing",
+			zap.String("namespace", namespace),
+			zap.String("name", name),
+			zap.Int("timeout", PrepullTimeout))
+	}
+
+	propagation := metaV1.DeletePropagationBackground
+	err := dsClient.Delete(ctx, name, metaV1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		a.Log.Error("unable to delete prepull daemonset",
+			zap.String("namespace", namespace),
+			zap.String("name", name),
+			zap.Error(err))
+	}
+}
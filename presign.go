@@ -0,0 +1,97 @@
+package pvci
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v6"
+)
+
+// MaxPresignedObjects limits the objects presigned by a single request.
+const MaxPresignedObjects = 10000
+
+// MaxPresignExpiry is the longest expiry S3 allows a presigned URL.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// PresignedObject is an object with a presigned GET URL.
+type PresignedObject struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+	URL  string `json:"url"`
+}
+
+// PresignHandler used by the HTTP POST /presign endpoint returns
+// presigned GET URLs for the objects of a PVCRequestConfig, as an
+// alternative to injecting them into a PVC. The URLs expire after the
+// expiry query parameter (e.g. ?expiry=24h or ?expiry=3600), default
+// one hour.
+func (a *API) PresignHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		expiry, err := parseTimeout(c.DefaultQuery("expiry", "1h"))
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		objects, err := a.Presign(*pvcRequestConfig, expiry)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"expires": time.Now().Add(expiry).UTC(),
+			"objects": objects,
+		})
+	}
+}
+
+// Presign lists the objects of a PVCRequestConfig, returning a GET URL
+// for each presigned to expire after expiry. Listings of more than
+// MaxPresignedObjects objects are rejected.
+func (a *API) Presign(pvcRequestConfig PVCRequestConfig, expiry time.Duration) ([]PresignedObject, error) {
+	if expiry > MaxPresignExpiry {
+		return nil, badRequest("expiry must not exceed %s", MaxPresignExpiry)
+	}
+
+	minioClient, err := a.getMinIOClient(pvcRequestConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]PresignedObject, 0)
+	err = a.listObjects(minioClient, pvcRequestConfig, func(object minio.ObjectInfo) error {
+		// directory markers have no content
+		if strings.HasSuffix(object.Key, "/") {
+			return nil
+		}
+
+		if len(objects) == MaxPresignedObjects {
+			return tooLarge("more than %d objects to presign, narrow the prefix", MaxPresignedObjects)
+		}
+
+		u, err := minioClient.PresignedGetObject(pvcRequestConfig.S3Bucket, object.Key, expiry, url.Values{})
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, PresignedObject{Key: object.Key, Size: object.Size, URL: u.String()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,80 @@
+package pvci
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxProgressSamples caps the progress history kept per injector.
+const MaxProgressSamples = 60
+
+// ProgressRetention is how long the progress history of a finished
+// injector remains available to GetStatus.
+const ProgressRetention = time.Hour
+
+// ProgressSample is the source volume usage of an injector at a time.
+type ProgressSample struct {
+	Time  time.Time `json:"time"`
+	Bytes int64     `json:"bytes"`
+}
+
+// progressHistory is a concurrency safe store of the recent progress
+// samples of injector Jobs, keyed by namespace and Job name.
+type progressHistory struct {
+	mu      sync.Mutex
+	samples map[string][]ProgressSample
+}
+
+func newProgressHistory() *progressHistory {
+	return &progressHistory{samples: make(map[string][]ProgressSample)}
+}
+
+// reset clears the history of a Job starting a new run.
+func (ph *progressHistory) reset(namespace string, jobName string) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	ph.samples[namespace+"/"+jobName] = make([]ProgressSample, 0)
+}
+
+// record appends a sample, dropping the oldest beyond MaxProgressSamples.
+func (ph *progressHistory) record(namespace string, jobName string, bytes int64) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	key := namespace + "/" + jobName
+	samples := append(ph.samples[key], ProgressSample{Time: time.Now().UTC(), Bytes: bytes})
+	if len(samples) > MaxProgressSamples {
+		samples = samples[len(samples)-MaxProgressSamples:]
+	}
+
+	ph.samples[key] = samples
+}
+
+// get returns a copy of the samples of a Job.
+func (ph *progressHistory) get(namespace string, jobName string) []ProgressSample {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+
+	return append([]ProgressSample{}, ph.samples[namespace+"/"+jobName]...)
+}
+
+// expire removes the history of a Job after ProgressRetention, unless
+// a new run has started.
+func (ph *progressHistory) expire(namespace string, jobName string) {
+	key := namespace + "/" + jobName
+
+	ph.mu.Lock()
+	samples := ph.samples[key]
+	ph.mu.Unlock()
+
+	time.AfterFunc(ProgressRetention, func() {
+		ph.mu.Lock()
+		defer ph.mu.Unlock()
+
+		current := ph.samples[key]
+		if len(current) == 0 || (len(samples) > 0 && current[0] == samples[0]) {
+			delete(ph.samples, key)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package pvci
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// requestAnnotation records the redacted request that created a PVC.
+const requestAnnotation = "pvci.txn2.com/request"
+
+// redactedValue replaces secrets in a redacted PVCRequestConfig.
+const redactedValue = "REDACTED"
+
+// redacted returns a copy of a PVCRequestConfig with its credentials
+// replaced by redactedValue, and the user info and query of its
+// CallbackURL removed.
+func (pvcRequestConfig PVCRequestConfig) redacted() PVCRequestConfig {
+	redact := func(s *string) {
+		if *s != "" {
+			*s = redactedValue
+		}
+	}
+
+	redact(&pvcRequestConfig.S3Key)
+	redact(&pvcRequestConfig.S3Secret)
+	redact(&pvcRequestConfig.S3ListKey)
+	redact(&pvcRequestConfig.S3ListSecret)
+	redact(&pvcRequestConfig.S3TransferKey)
+	redact(&pvcRequestConfig.S3TransferSecret)
+
+	if pvcRequestConfig.CallbackURL != "" {
+		u, err := url.Parse(pvcRequestConfig.CallbackURL)
+		if err != nil {
+			pvcRequestConfig.CallbackURL = redactedValue
+		} else {
+			u.User = nil
+			u.RawQuery = ""
+			pvcRequestConfig.CallbackURL = u.String()
+		}
+	}
+
+	return pvcRequestConfig
+}
+
+// stampRequest adds the redacted request of a create to the
+// annotations of the PVC it produces, recording how it was built.
+func stampRequest(annotations map[string]string, pvcRequestConfig PVCRequestConfig) {
+	request, err := json.Marshal(pvcRequestConfig.redacted())
+	if err != nil {
+		return
+	}
+
+	annotations[requestAnnotation] = string(request)
+}
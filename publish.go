@@ -0,0 +1,43 @@
+package pvci
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPublishSubject prefixes the subjects lifecycle events are
+// published to when Config.PublishSubject is not set.
+const DefaultPublishSubject = "pvci"
+
+// Publisher publishes messages to a subject of an event bus. Package
+// natspub implements it for NATS.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// publish sends the CallbackPayload of an operation to the configured
+// Publisher on "<PublishSubject>.<operation>". Delivery is
+// asynchronous and failures are logged.
+func (a *API) publish(payload CallbackPayload) {
+	if a.Publisher == nil {
+		return
+	}
+
+	subject := a.PublishSubject + "." + payload.Operation
+
+	go func() {
+		data, err := json.Marshal(payload)
+		if err == nil {
+			err = a.Publisher.Publish(subject, data)
+		}
+		if err != nil {
+			a.Log.Warn("publish failed",
+				zap.String("subject", subject),
+				zap.String("namespace", payload.Namespace),
+				zap.String("name", payload.Name),
+				zap.Error(err),
+			)
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package pvci
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"go.uber.org/zap"
+)
+
+// PushTimeout bounds a push of create metrics to the Pushgateway.
+const PushTimeout = 10 * time.Second
+
+// pushMetrics pushes the metrics of a completed create Operation to
+// Config.PushgatewayURL, labelled by outcome and the metric labels of
+// the request. Pushes are grouped by job, instance and outcome, each
+// push replacing the metrics of the last create with its outcome, so
+// the groups kept by the Pushgateway do not grow with the volumes
+// created.
+func (a *API) pushMetrics(op Operation, metricLabels map[string]string) {
+	if a.PushgatewayURL == "" {
+		return
+	}
+
+	labels := a.metricLabels(metricLabels)
+	labels["namespace"] = op.Namespace
+	gauge := func(name string, help string, value float64) prometheus.Gauge {
+		g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help, ConstLabels: labels})
+		g.Set(value)
+		return g
+	}
+
+	finished := time.Now()
+	if op.Finished != nil {
+		finished = *op.Finished
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		gauge("pvci_create_bytes", "Bytes of object data injected by the create.", float64(op.Bytes)),
+		gauge("pvci_create_objects", "Objects injected by the create.", float64(op.Objects)),
+		gauge("pvci_create_duration_seconds", "Duration of the create.", finished.Sub(op.Started).Seconds()),
+		gauge("pvci_create_completion_timestamp_seconds", "Unix time the create completed.", float64(finished.Unix())),
+	)
+
+	err := push.New(a.PushgatewayURL, a.PushgatewayJob).
+		Client(&http.Client{Timeout: PushTimeout}).
+		Gatherer(registry).
+		Grouping("instance", a.PushgatewayInstance).
+		Grouping("outcome", op.Status).
+		Push()
+	if err != nil {
+		a.Log.Warn("unable to push create metrics",
+			zap.String("pushgateway", a.PushgatewayURL),
+			zap.String("namespace", op.Namespace),
+			zap.String("name", op.Name),
+			zap.Error(err),
+		)
+	}
+}
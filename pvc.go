@@ -0,0 +1,563 @@
+package pvci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Event reasons of PVC provisioning, see the persistentvolume controller
+// and external-provisioner.
+const (
+	ProvisioningFailed    = "ProvisioningFailed"
+	ExternalProvisioning  = "ExternalProvisioning"
+	Provisioning          = "Provisioning"
+	ProvisioningSucceeded = "ProvisioningSucceeded"
+	WaitForFirstConsumer  = "WaitForFirstConsumer"
+	WaitForPodScheduled   = "WaitForPodScheduled"
+)
+
+// provisioningReasons are the event reasons reported while
+// waiting for a PVC to bind.
+var provisioningReasons = map[string]bool{
+	ProvisioningFailed:    true,
+	ExternalProvisioning:  true,
+	Provisioning:          true,
+	ProvisioningSucceeded: true,
+	WaitForFirstConsumer:  true,
+	WaitForPodScheduled:   true,
+}
+
+// ProvisioningFailedPolls is the number of consecutive PVC polls a
+// ProvisioningFailed event must persist over to fail a create, letting
+// provisioners retry transient failures.
+const ProvisioningFailedPolls = 4
+
+// CloneRetryInterval is the number of seconds before retrying the final
+// clone PVC create or bind wait, doubling with each retry.
+const CloneRetryInterval = 2
+
+// warningsAnnotation holds the newline separated warnings of a create
+// that succeeded with problems, such as a leaked source PVC.
+const warningsAnnotation = "pvci.txn2.com/warnings"
+
+// readyAnnotation is stamped "true" on a final PVC once its create has
+// fully succeeded, the signal consumers gate mounting on.
+const readyAnnotation = "pvci.txn2.com/ready"
+
+// PVCDeletionTimeout is the number of seconds to wait for a PVC to be
+// removed after its finalizers are cleared.
+const PVCDeletionTimeout = 60
+
+// replaceTerminatingPVC clears the finalizers of a PVCI managed PVC stuck
+// in Terminating and waits for it to be removed so a create may proceed.
+// It returns false without error when the PVC is not eligible, leaving
+// the caller to report the existing PVC.
+func (a *API) replaceTerminatingPVC(pvc *coreV1.PersistentVolumeClaim, volName string) (bool, error) {
+	if !a.ForceReplaceTerminating || pvc.DeletionTimestamp == nil {
+		return false, nil
+	}
+
+	if pvc.Labels[a.labelKey("vol")] != volName {
+		return false, nil
+	}
+
+	a.Log.Warn("Removing finalizers from terminating PVC",
+		zap.String("namespace", pvc.Namespace),
+		zap.String("name", pvc.Name),
+		zap.Strings("finalizers", pvc.Finalizers),
+	)
+
+	err := a.removeFinalizers(pvc.Namespace, pvc.Name)
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return false, err
+	}
+
+	err = a.waitPVCDeleted(pvc.Namespace, pvc.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reclaimOrphanedSource deletes a PVCI managed source PVC left behind by
+// a failed create, along with its finished injector Job, so a retry may
+// proceed. It returns false without error when the PVC is not eligible,
+// including while an injector Job is still running against it. Creates
+// call it only once their request has been validated.
+func (a *API) reclaimOrphanedSource(pvc *coreV1.PersistentVolumeClaim, volName string) (bool, error) {
+	if !a.ReclaimOrphanedSource {
+		return false, nil
+	}
+
+	if pvc.Labels[a.labelKey("vol")] != volName || pvc.Labels[a.labelKey("debug-retained")] == "true" ||
+		pvc.Labels[a.labelKey("awaiting-clone")] == "true" {
+		return false, nil
+	}
+
+	ctx := context.Background()
+	jobName := fmt.Sprintf("%s-injector", volName)
+
+	job, err := a.getJob(pvc.Namespace, jobName)
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return false, err
+	}
+
+	if err == nil {
+		if job.Status.Active > 0 {
+			return false, nil
+		}
+
+		propagation := metaV1.DeletePropagationBackground
+		err = a.Cs.BatchV1().Jobs(pvc.Namespace).Delete(ctx, jobName, metaV1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apiErrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	a.Log.Warn("Reclaiming orphaned source PVC",
+		zap.String("namespace", pvc.Namespace),
+		zap.String("name", pvc.Name),
+		zap.Strings("finalizers", pvc.Finalizers),
+	)
+
+	err = a.Cs.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metaV1.DeleteOptions{})
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return false, err
+	}
+
+	err = a.removeFinalizers(pvc.Namespace, pvc.Name)
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return false, err
+	}
+
+	err = a.waitPVCDeleted(pvc.Namespace, pvc.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// pvcPhase returns the phase of a PVC, reporting Terminating
+// for PVCs pending deletion.
+func pvcPhase(pvc *coreV1.PersistentVolumeClaim) string {
+	if pvc.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+
+	return string(pvc.Status.Phase)
+}
+
+// removeFinalizers clears all finalizers of a PVC.
+func (a *API) removeFinalizers(namespace string, name string) error {
+	ctx := context.Background()
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(namespace).Patch(
+		ctx, name, types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// waitPVCDeleted polls until a PVC no longer exists.
+func (a *API) waitPVCDeleted(namespace string, name string) error {
+	for i := 0; i < PVCDeletionTimeout; i++ {
+		_, err := a.getPVC(namespace, name)
+		if apiErrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("PVC %s was not deleted in %d seconds", name, PVCDeletionTimeout)
+}
+
+// provisioningEvent returns the latest provisioning event of a PVC,
+// or nil when there is none.
+func (a *API) provisioningEvent(pvc *coreV1.PersistentVolumeClaim) (*coreV1.Event, error) {
+	return a.pvcEvent(pvc, provisioningReasons)
+}
+
+// pvcEvent returns the latest event of a PVC with one of reasons, or nil
+// when there is none.
+func (a *API) pvcEvent(pvc *coreV1.PersistentVolumeClaim, reasons map[string]bool) (*coreV1.Event, error) {
+	events, err := a.Cs.CoreV1().Events(pvc.Namespace).List(context.Background(), metaV1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "PersistentVolumeClaim",
+			"involvedObject.name": pvc.Name,
+		}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *coreV1.Event
+	for i, event := range events.Items {
+		if event.InvolvedObject.Name != pvc.Name || event.InvolvedObject.UID != pvc.UID {
+			continue
+		}
+
+		if !reasons[event.Reason] {
+			continue
+		}
+
+		if latest == nil || !event.LastTimestamp.Before(&latest.LastTimestamp) {
+			latest = &events.Items[i]
+		}
+	}
+
+	return latest, nil
+}
+
+// retryClone runs a step of the final clone PVC up to CloneRetries more
+// times with a doubling backoff while it fails with a retryable error.
+func (a *API) retryClone(step string, name string, retryable func(error) bool, fn func() error) error {
+	err := fn()
+	for retry := 0; retry < a.CloneRetries && err != nil && retryable(err); retry++ {
+		wait := time.Duration(CloneRetryInterval<<uint(retry)) * time.Second
+
+		a.Log.Warn("retrying clone PVC "+step,
+			zap.String("name", name),
+			zap.Int("retry", retry+1),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+
+		time.Sleep(wait)
+		err = fn()
+	}
+
+	return err
+}
+
+// isTransient reports whether an API error may succeed on retry.
+// Errors not returned by the API server, such as connection
+// failures, are considered transient.
+func isTransient(err error) bool {
+	var statusErr apiErrors.APIStatus
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+
+	return apiErrors.IsServerTimeout(err) ||
+		apiErrors.IsTimeout(err) ||
+		apiErrors.IsTooManyRequests(err) ||
+		apiErrors.IsInternalError(err) ||
+		apiErrors.IsServiceUnavailable(err) ||
+		apiErrors.IsUnexpectedServerError(err)
+}
+
+// releaseSourcePVC removes the finalizer holding a deleted source PVC,
+// retrying up to Config.FinalizerPatchRetries times with a backoff
+// starting at CloneRetryInterval seconds. A PVC already gone or without
+// finalizers needs no patch.
+func (a *API) releaseSourcePVC(namespace string, name string) error {
+	err := a.patchFinalizer(namespace, name)
+	for retry := 0; retry < a.FinalizerPatchRetries && err != nil; retry++ {
+		wait := time.Duration(CloneRetryInterval<<uint(retry)) * time.Second
+
+		a.Log.Warn("retrying source PVC finalizer patch",
+			zap.String("name", name),
+			zap.String("namespace", namespace),
+			zap.Int("retry", retry+1),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+
+		time.Sleep(wait)
+		err = a.patchFinalizer(namespace, name)
+	}
+
+	return err
+}
+
+// cleanupFinal deletes the final and source PVCs of a create failing
+// once its final PVC was requested, releasing any finalizer holding
+// them so the failed create leaves nothing behind.
+func (a *API) cleanupFinal(namespace string, name string, srcPVCName string) {
+	pvcClient := a.Cs.CoreV1().PersistentVolumeClaims(namespace)
+
+	a.Log.Info("Cleaning up failed create",
+		zap.String("namespace", namespace),
+		zap.String("pvc", name),
+		zap.String("src_pvc", srcPVCName),
+	)
+
+	for _, pvcName := range []string{name, srcPVCName} {
+		err := pvcClient.Delete(context.Background(), pvcName, metaV1.DeleteOptions{})
+		if apiErrors.IsNotFound(err) {
+			continue
+		}
+		if err == nil {
+			err = a.releaseSourcePVC(namespace, pvcName)
+		}
+		if err != nil {
+			a.Log.Error("unable to delete PVC",
+				zap.String("namespace", namespace),
+				zap.String("name", pvcName),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// patchFinalizer removes the first finalizer of a PVC.
+func (a *API) patchFinalizer(namespace string, name string) error {
+	pvc, err := a.getPVC(namespace, name)
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(pvc.Finalizers) == 0 {
+		a.Log.Info("source PVC has no finalizers, skipping patch",
+			zap.String("name", name),
+			zap.String("namespace", namespace),
+		)
+		return nil
+	}
+
+	// patch pvc to remove finalizers for deletion
+	po := &PatchOperations{
+		{
+			Op:   "remove",
+			Path: "/metadata/finalizers/0",
+		},
+	}
+
+	poJson, _ := json.Marshal(po)
+
+	_, err = a.Cs.CoreV1().PersistentVolumeClaims(namespace).Patch(
+		context.Background(), name, types.JSONPatchType, poJson, metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// addWarning appends a warning to the warnings annotation of a PVC.
+func (a *API) addWarning(pvc *coreV1.PersistentVolumeClaim, warning string) error {
+	warnings := append(Warnings(pvc), warning)
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				warningsAnnotation: strings.Join(warnings, "\n"),
+			},
+		},
+	})
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(
+		context.Background(), pvc.Name, types.MergePatchType, patch, metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// keepSourceForClone labels the source PVC of a final PVC not yet
+// bound awaiting-clone, keeping it from the orphan cleanup of Reconcile
+// until the clone binds.
+func (a *API) keepSourceForClone(namespace string, srcPVCName string) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{
+				a.labelKey("awaiting-clone"): "true",
+			},
+		},
+	})
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(namespace).Patch(
+		context.Background(), srcPVCName, types.MergePatchType, patch, metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// annotatePVC merges annotations into those of a PVC.
+func (a *API) annotatePVC(namespace string, name string, annotations map[string]string) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(namespace).Patch(
+		context.Background(), name, types.MergePatchType, patch, metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// markReady stamps the ready annotation on the final PVC of a create.
+func (a *API) markReady(namespace string, name string) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				readyAnnotation: "true",
+			},
+		},
+	})
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(namespace).Patch(
+		context.Background(), name, types.MergePatchType, patch, metaV1.PatchOptions{},
+	)
+
+	return err
+}
+
+// Ready reports whether a PVC is the final PVC of a completed create,
+// populated and safe to mount.
+func Ready(pvc *coreV1.PersistentVolumeClaim) bool {
+	return pvc.Annotations[readyAnnotation] == "true"
+}
+
+// Warnings returns the warnings annotated on a PVC by a create that
+// succeeded with problems.
+func Warnings(pvc *coreV1.PersistentVolumeClaim) []string {
+	warnings := make([]string, 0)
+	if pvc.Annotations[warningsAnnotation] == "" {
+		return warnings
+	}
+
+	return append(warnings, strings.Split(pvc.Annotations[warningsAnnotation], "\n")...)
+}
+
+// checkStorageClasses validates that the storage classes of a VolConfig
+// are permitted by AllowedStorageClasses (an empty list allows any).
+func (a *API) checkStorageClasses(volConfig VolConfig) error {
+	if len(a.AllowedStorageClasses) == 0 {
+		return nil
+	}
+
+	for _, name := range []string{volConfig.StorageClass, volConfig.finalStorageClass()} {
+		allowed := false
+		for _, sc := range a.AllowedStorageClasses {
+			if sc == name {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return forbidden("storage class %q is not allowed", name)
+		}
+	}
+
+	return nil
+}
+
+// storageClassNotFound returns a STORAGE_CLASS_NOT_FOUND Error for a
+// storage class missing from the cluster.
+func storageClassNotFound(name string) error {
+	return newError(ErrCodeStorageClassNotFound, http.StatusBadRequest, "storage class %s does not exist", name)
+}
+
+// verifyStorageClasses validates that the storage classes of a
+// VolConfig exist when Config.VerifyStorageClass is set, rather than
+// leaving PVCs pending until checkPVC times out. Without read access
+// to storage classes the check is skipped.
+func (a *API) verifyStorageClasses(volConfig VolConfig) error {
+	if !a.VerifyStorageClass {
+		return nil
+	}
+
+	for _, name := range []string{volConfig.StorageClass, volConfig.finalStorageClass()} {
+		_, err := a.getStorageClass(name)
+		if apiErrors.IsNotFound(err) {
+			return storageClassNotFound(name)
+		}
+		if apiErrors.IsForbidden(err) {
+			a.Log.Warn("unable to verify storage class",
+				zap.String("storage_class", name),
+				zap.Error(err),
+			)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCrossClassClone validates that the source PVC of a VolConfig may
+// be cloned into its final storage class. CSI clones are provisioned by
+// the driver of the source volume, so both classes must share a
+// provisioner.
+func (a *API) checkCrossClassClone(volConfig VolConfig) error {
+	finalStorageClass := volConfig.finalStorageClass()
+	if finalStorageClass == volConfig.StorageClass {
+		return nil
+	}
+
+	provisioners := make([]string, 0)
+	for _, name := range []string{volConfig.StorageClass, finalStorageClass} {
+		sc, err := a.getStorageClass(name)
+		if apiErrors.IsNotFound(err) {
+			return storageClassNotFound(name)
+		}
+		if err != nil {
+			return err
+		}
+		provisioners = append(provisioners, sc.Provisioner)
+	}
+
+	if provisioners[0] != provisioners[1] {
+		return badRequest("unable to clone from storage class %s (%s) to %s (%s), cross-class clones require the same provisioner",
+			volConfig.StorageClass, provisioners[0], finalStorageClass, provisioners[1])
+	}
+
+	return nil
+}
+
+// cloneIncompatibleMessages are fragments of errors from the API server
+// and CSI drivers rejecting a clone that will never succeed, lower case.
+var cloneIncompatibleMessages = []string{
+	"same storage class for cloning",
+	"clone_volume",
+	"cloning is not supported",
+	"does not support cloning",
+	"does not support volume cloning",
+}
+
+// cloneIncompatible reports whether an error message rejects a clone
+// outright.
+func cloneIncompatible(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range cloneIncompatibleMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cloneIncompatibleError returns a CLONE_INCOMPATIBLE Error for the
+// clone PVC name in storageClass rejected with message.
+func cloneIncompatibleError(name string, storageClass string, message string) error {
+	return newError(ErrCodeCloneIncompatible, http.StatusUnprocessableEntity,
+		"storage class %s is unable to clone PVC %s, use a final storage class whose driver supports cloning the source: %s",
+		storageClass, name, message)
+}
@@ -2,24 +2,34 @@ package pvci
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
 // PatchOperation
@@ -41,10 +51,52 @@ type StatusReport struct {
 	PVCHasError      bool
 	PVCError         string
 	PVCStatus        coreV1.PersistentVolumeClaimStatus
+
+	// PVName is the PersistentVolume bound to the PVC.
+	PVName string
+
+	// PVCProvisioning is the latest provisioning event,
+	// "<reason>: <message>", of a Pending PVC.
+	PVCProvisioning string
+
+	// Warnings of a create that succeeded with problems.
+	Warnings []string
+
+	// Ready is set once the create of the PVC has fully succeeded,
+	// through injection, clone and verification.
+	Ready bool
+
+	// Progress is the recent source volume usage of the injector,
+	// sampled by the PVCI replica running the create.
+	Progress []ProgressSample
+
+	// InjectorPods are all pods of the injector Job, newest first,
+	// including those of retries. InjectorState is the phase of the
+	// newest, the active attempt. InjectorAttempts counts the pods and
+	// InjectorFailedAttempts those that failed.
+	InjectorPods           []InjectorPod
+	InjectorAttempts       int
+	InjectorFailedAttempts int
+}
+
+// InjectorPod is an injector pod of a volume, for reading its logs
+// or correlating failures with its node.
+type InjectorPod struct {
+	Name                  string                   `json:"name"`
+	Node                  string                   `json:"node"`
+	Phase                 coreV1.PodPhase          `json:"phase"`
+	Created               time.Time                `json:"created"`
+	InitContainerStatuses []coreV1.ContainerStatus `json:"init_container_statuses"`
+	ContainerStatuses     []coreV1.ContainerStatus `json:"container_statuses"`
 }
 
 // S3Config structures authentication, bucket and prefix
 // configuration used to pull objects from an S3/MinIO object cluster.
+//
+// S3AsOf optionally pins a versioned bucket to the object versions
+// current at an RFC3339 timestamp, both for sizing and copying (mc
+// --rewind, requires an mc image from 2020-12 or later). Non-versioned
+// buckets fall back to the latest objects.
 type S3Config struct {
 	S3Endpoint string `json:"s3_endpoint"`
 	S3SSL      bool   `json:"s3_ssl"`
@@ -52,16 +104,291 @@ type S3Config struct {
 	S3Prefix   string `json:"s3_prefix"`
 	S3Key      string `json:"s3_key"`
 	S3Secret   string `json:"s3_secret"`
+	S3AsOf     string `json:"s3_as_of"`
+
+	// S3TLSServerName overrides the TLS server name (SNI) presented to
+	// S3Endpoint, for gateways fronted by a shared certificate.
+	// Injectors connect by the server name on the transfer endpoint's
+	// port, which must resolve in the cluster or by host aliases.
+	S3TLSServerName string `json:"s3_tls_server_name"`
+
+	// SizeSource selects how objects are sized, "list" (default) lists
+	// the bucket while "inventory" reads the S3 Inventory report whose
+	// manifest.json is at S3InventoryKey, making sizing feasible for
+	// buckets too large to list.
+	SizeSource     string `json:"size_source"`
+	S3InventoryKey string `json:"s3_inventory_key"`
+
+	// RefreshSize bypasses the size cache.
+	RefreshSize bool `json:"refresh_size"`
+
+	// S3Transport overrides the configured S3Transport tuning
+	// for the request.
+	S3Transport TransportTuning `json:"s3_transport"`
+
+	// AllowKeyCollisions sizes and copies objects whose keys are also
+	// directories of other keys, e.g. foo and foo/bar, rather than
+	// failing with KEY_COLLISION. The copy may fail or skip objects.
+	AllowKeyCollisions bool `json:"allow_key_collisions"`
+
+	// S3TransferEndpoint is used by the injector to copy objects, while
+	// sizing lists through S3Endpoint. Empty uses S3Endpoint for both.
+	S3TransferEndpoint string `json:"s3_transfer_endpoint"`
+
+	// S3Delimiter separates the directories of object keys, "/" when
+	// empty. Keys are copied with other delimiters mapped to directories
+	// through a copy plan (mc transport only).
+	S3Delimiter string `json:"s3_delimiter"`
+
+	// S3ListKey and S3ListSecret are the credentials sizing lists the
+	// bucket with, and S3TransferKey and S3TransferSecret those the
+	// injector copies with, for buckets permitting listing and
+	// downloads to different principals. Each pair defaults to S3Key
+	// and S3Secret; S3ListAnonymous and S3TransferAnonymous make the
+	// respective requests without credentials.
+	S3ListKey           string `json:"s3_list_key"`
+	S3ListSecret        string `json:"s3_list_secret"`
+	S3ListAnonymous     bool   `json:"s3_list_anonymous"`
+	S3TransferKey       string `json:"s3_transfer_key"`
+	S3TransferSecret    string `json:"s3_transfer_secret"`
+	S3TransferAnonymous bool   `json:"s3_transfer_anonymous"`
+
+	// S3SignatureVersion signs requests with SignatureV4 (default) or
+	// SignatureV2 for legacy object stores without V4 support.
+	S3SignatureVersion string `json:"s3_signature_version"`
+
+	// S3Region is the region of S3Bucket, e.g. eu-central-1 for AWS
+	// S3 buckets outside us-east-1. Empty discovers the region with a
+	// bucket location request.
+	S3Region string `json:"s3_region"`
+
+	// ContentTypes selects only objects whose content type is one of
+	// these media types, e.g. application/parquet. Listings do not
+	// carry content types, so every listed object is stated, and
+	// matching objects are copied through a copy plan (mc transport
+	// only).
+	ContentTypes []string `json:"content_types"`
+}
+
+// transferEndpoint returns the endpoint the injector copies from.
+func (s3Config S3Config) transferEndpoint() string {
+	if s3Config.S3TransferEndpoint != "" {
+		return s3Config.S3TransferEndpoint
+	}
+
+	return s3Config.S3Endpoint
+}
+
+// injectorEndpoint returns the host:port the injector connects to, the
+// transfer endpoint addressed by S3TLSServerName when that is set.
+func (s3Config S3Config) injectorEndpoint() string {
+	endpoint := s3Config.transferEndpoint()
+	if !s3Config.S3SSL || s3Config.S3TLSServerName == "" {
+		return endpoint
+	}
+
+	if _, port, err := net.SplitHostPort(endpoint); err == nil {
+		return net.JoinHostPort(s3Config.S3TLSServerName, port)
+	}
+
+	return s3Config.S3TLSServerName
+}
+
+// delimiter returns the directory delimiter of object keys.
+func (s3Config S3Config) delimiter() string {
+	if s3Config.S3Delimiter != "" {
+		return s3Config.S3Delimiter
+	}
+
+	return "/"
 }
 
 // VolConfig is part of the PVCRequestConfig and used to specify
 // the name of the volume to create the the Kubernetes storage class.
 // run `kubectl get StorageClass` to see a list of available storage
 // classed for a cluster.
+//
+// SizeMultiplier scales the requested storage after the overage is
+// applied, below 1 for known sparse or compressible data and above 1
+// for data that expands on disk. Zero leaves the size unchanged.
+//
+// FastStart creates the source PVC at Config.FastStartSize while the
+// bucket is sized and resizes it once bound. It requires a storage
+// class allowing volume expansion and is ignored otherwise.
+//
+// DebugKeepSource leaves the source PVC in place after the create
+// completes, labelled debug-retained, for inspecting the transferred
+// content. Retained source PVCs are never reclaimed automatically.
+//
+// FinalStorageClass places the final read-only clone on a different
+// storage class than the source PVC staged in StorageClass. Both must
+// be provisioned by the same driver. Empty uses StorageClass.
+//
+// VerifyConsumable fails the create unless a pod mounting the final
+// PVC read-only finds data on it.
+//
+// Populator creates the final PVC with a volume populator as its data
+// source instead of injecting the objects through a source PVC and
+// Job.
+//
+// RequestedSize sizes the volumes for a known number of bytes rather
+// than the measured bucket size, which is still listed for the object
+// count. SkipSizeCompute bypasses listing entirely for a supplied
+// RequestedSize, leaving the object count annotated as 0 until the
+// landed files are counted.
+//
+// AutoGrow annotates the final PVC pvci.txn2.com/autogrow for
+// expansion by AutoGrow as it fills.
+//
+// OveragePercent replaces the configured overage of the storage class
+// for datasets needing more or less filesystem slack, from 0 to
+// MaxOveragePercent.
+//
+// SrcAccessMode and FinalAccessMode replace the ReadWriteOnce access
+// mode of the source PVC and the ReadOnlyMany access mode of the final
+// PVC, for drivers not supporting them.
+//
+// VolumeStrategy selects between cloning an injected source PVC, the
+// default, and injecting the final PVC directly. Creates not setting
+// it inject directly when the storage class can not clone.
 type VolConfig struct {
-	Namespace    string `json:"namespace"`
-	Name         string `json:"name"`
-	StorageClass string `json:"storage_class"`
+	Namespace         string  `json:"namespace"`
+	Name              string  `json:"name"`
+	StorageClass      string  `json:"storage_class"`
+	FinalStorageClass string  `json:"final_storage_class"`
+	SizeMultiplier    float64 `json:"size_multiplier"`
+	FastStart         bool    `json:"fast_start"`
+	DebugKeepSource   bool    `json:"debug_keep_source"`
+	VerifyConsumable  bool    `json:"verify_consumable"`
+	RequestedSize     int64   `json:"requested_size"`
+	SkipSizeCompute   bool    `json:"skip_size_compute"`
+	AutoGrow          bool    `json:"autogrow"`
+	OveragePercent    *int    `json:"overage_pct"`
+	SrcAccessMode     string  `json:"src_access_mode"`
+	FinalAccessMode   string  `json:"final_access_mode"`
+	VolumeStrategy    string  `json:"volume_strategy"`
+
+	Populator *PopulatorRef `json:"populator"`
+}
+
+// MaxOveragePercent is the largest OveragePercent a request may set.
+const MaxOveragePercent = 500
+
+// finalStorageClass returns the storage class of the final clone PVC.
+func (volConfig VolConfig) finalStorageClass() string {
+	if volConfig.FinalStorageClass != "" {
+		return volConfig.FinalStorageClass
+	}
+
+	return volConfig.StorageClass
+}
+
+// srcAccessMode returns the access mode of the source PVC.
+func (volConfig VolConfig) srcAccessMode() coreV1.PersistentVolumeAccessMode {
+	if volConfig.SrcAccessMode != "" {
+		return coreV1.PersistentVolumeAccessMode(volConfig.SrcAccessMode)
+	}
+
+	return coreV1.ReadWriteOnce
+}
+
+// finalAccessMode returns the access mode of the final PVC.
+func (volConfig VolConfig) finalAccessMode() coreV1.PersistentVolumeAccessMode {
+	if volConfig.FinalAccessMode != "" {
+		return coreV1.PersistentVolumeAccessMode(volConfig.FinalAccessMode)
+	}
+
+	return coreV1.ReadOnlyMany
+}
+
+// checkAccessModes validates the access modes of a VolConfig.
+func checkAccessModes(volConfig VolConfig) error {
+	for _, setting := range []struct {
+		option string
+		mode   string
+	}{
+		{"src_access_mode", volConfig.SrcAccessMode},
+		{"final_access_mode", volConfig.FinalAccessMode},
+	} {
+		switch coreV1.PersistentVolumeAccessMode(setting.mode) {
+		case "", coreV1.ReadWriteOnce, coreV1.ReadOnlyMany, coreV1.ReadWriteMany:
+		default:
+			return badRequest("%s must be %s, %s or %s", setting.option,
+				coreV1.ReadWriteOnce, coreV1.ReadOnlyMany, coreV1.ReadWriteMany)
+		}
+	}
+
+	return nil
+}
+
+// InjectorConfig is part of the PVCRequestConfig and used to tune
+// the behavior of the injector Job that copies objects into the PVC.
+//
+// PreserveMetadata runs mc with --preserve to retain file timestamps
+// and attributes. Attributes are only restored for objects that carry
+// them in their metadata (objects uploaded with `mc cp --preserve` to
+// MinIO or AWS S3); other backends and objects fall back to the
+// time of copy.
+//
+// Labels are added to the injector pod, for example to match
+// NetworkPolicies allowing egress to the object store. Keys under the
+// PVCI label prefix are reserved.
+//
+// Annotations are added to the injector pod, overriding the configured
+// InjectorAnnotations.
+//
+// Transport selects the copy tool, TransportMC (default), TransportRclone
+// or TransportAWSCLI. Command replaces the transport's command, running
+// with its image and object store environment.
+//
+// PartitionBy routes objects into subdirectories derived from their keys
+// (mc transport only).
+//
+// StallTimeout and MaxDuration, in seconds, override the configured
+// StallTimeout and MaxInjectorDuration of the injector.
+//
+// Sorted copies objects one at a time in sorted key order through a
+// copy plan (mc transport only), for consumers benefiting from the
+// on-disk locality of sequential reads, at the cost of throughput.
+//
+// ExtraVolumes are added to the injector pod and ExtraVolumeMounts to
+// its copy container, e.g. for a CA bundle or scratch space. Volumes
+// managed by PVCI (srcpvc, plan and mc-config) are reserved.
+//
+// Shards splits the copy plan among that many pods of an Indexed
+// Job, each copying the objects of its JOB_COMPLETION_INDEX, at most
+// Parallelism (default Shards) at once (mc transport only). The pods
+// share the source PVC, which must be ReadWriteMany.
+//
+// TransferRetries and TransferTimeout, in seconds, override the
+// configured InjectorTransferRetries and InjectorTransferTimeout of
+// the copy tool.
+//
+// HostAliases are added to the injector pod's hosts file after the
+// configured InjectorHostAliases, and DNSPolicy and DNSConfig replace
+// the configured InjectorDNSPolicy and InjectorDNSConfig, for object
+// stores the cluster DNS can not resolve.
+type InjectorConfig struct {
+	PreserveMetadata bool              `json:"preserve_metadata"`
+	Labels           map[string]string `json:"labels"`
+	Annotations      map[string]string `json:"annotations"`
+	Transport        string            `json:"transport"`
+	Command          []string          `json:"command"`
+	PartitionBy      *PartitionBy      `json:"partition_by"`
+	StallTimeout     int64             `json:"stall_timeout"`
+	MaxDuration      int64             `json:"max_duration"`
+	Sorted           bool              `json:"sorted"`
+	TransferRetries  int               `json:"transfer_retries"`
+	TransferTimeout  int64             `json:"transfer_timeout"`
+	Shards           int               `json:"shards"`
+	Parallelism      int               `json:"parallelism"`
+
+	ExtraVolumes      []coreV1.Volume      `json:"extra_volumes"`
+	ExtraVolumeMounts []coreV1.VolumeMount `json:"extra_volume_mounts"`
+
+	HostAliases []coreV1.HostAlias   `json:"host_aliases"`
+	DNSPolicy   coreV1.DNSPolicy     `json:"dns_policy"`
+	DNSConfig   *coreV1.PodDNSConfig `json:"dns_config"`
 }
 
 // PVCRequestConfig is the primary configuration structure for describing
@@ -70,6 +397,27 @@ type VolConfig struct {
 type PVCRequestConfig struct {
 	S3Config
 	VolConfig
+	InjectorConfig
+
+	// CallbackURL receives a CallbackPayload when a create or
+	// delete of the volume completes.
+	CallbackURL string `json:"callback_url"`
+
+	// TraceParent is the trace context read from the request's
+	// Config.TraceHeader, stamped on created resources and logs.
+	TraceParent string `json:"-"`
+
+	// CopyStrategy is the copy strategy of the injector, selected
+	// by the object count once sized.
+	CopyStrategy string `json:"-"`
+
+	// Estimate is the Estimate /create sized the request by, reused by
+	// the create in place of listing the bucket again.
+	Estimate *Estimate `json:"-"`
+
+	// MetricLabels label the metrics of the create by the configured
+	// Config.MetricLabels. They are not added to any resource.
+	MetricLabels map[string]string `json:"metric_labels"`
 }
 
 // Config configures the API
@@ -79,21 +427,288 @@ type Config struct {
 	VolumeOveragePercent int
 	AvgMPS               int
 	MCImage              string
-	Log                  *zap.Logger
-	Cs                   *kubernetes.Clientset
+	RcloneImage          string
+	AWSCLIImage          string
+	VerifyImage          string
+	DefaultNamespace     string
+	AllowedNamespaces    []string
+
+	// VerifyStorageClass rejects creates naming a storage class missing
+	// from the cluster with STORAGE_CLASS_NOT_FOUND before any PVC is
+	// created.
+	VerifyStorageClass bool
+
+	// RequireCloneSupport rejects creates whose storage class is not
+	// provisioned by a CSI driver detected at startup with
+	// CLONE_INCOMPATIBLE, since the final clone PVC would never bind,
+	// rather than falling back to VolumeStrategyDirectInject.
+	RequireCloneSupport bool
+
+	// SystemNamespace holds the operational objects of PVCI itself,
+	// such as image pre-pulls, apart from the namespaces it provisions
+	// into. Empty uses DefaultNamespace.
+	SystemNamespace string
+
+	// AllowedStorageClasses restricts the storage classes of created
+	// PVCs, an empty list allows any.
+	AllowedStorageClasses []string
+
+	LabelPrefix      string
+	CleanupOnFailure bool
+	CallbackSecret   string
+
+	// CallbackAllowedHosts lists the hosts a request's callback_url
+	// may name, exactly or by *.domain wildcards. Callbacks are
+	// disabled while it is empty.
+	CallbackAllowedHosts []string
+
+	// MCImageAllowedTags, MCImageMinRelease and MCImageMaxRelease
+	// restrict the MCImage tag to supported mc versions, checked by
+	// NewApi. Releases bound the range inclusively as an mc release
+	// tag or a 2006-01-02 date; allowed tags are accepted regardless.
+	MCImageAllowedTags []string
+	MCImageMinRelease  string
+	MCImageMaxRelease  string
+
+	// StorageClassOverage overrides VolumeOveragePercent by storage
+	// class, for filesystems needing more or less slack.
+	StorageClassOverage map[string]int
+
+	// SizeGranularity rounds PVC storage requests up to a multiple of
+	// this many bytes, matching drivers that provision in fixed
+	// increments such as 1Gi. Zero requests the exact size.
+	SizeGranularity int64
+
+	// SizeCacheTTL caches GetSize results for repeated datasets,
+	// zero disables the cache.
+	SizeCacheTTL time.Duration
+
+	// StorageClassCacheTTL caches the StorageClasses read by create
+	// checks, zero disables the cache.
+	StorageClassCacheTTL time.Duration
+
+	// MaxBodySize limits the bytes read from request bodies, zero
+	// uses DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// S3Transport tunes the connection pool and keep-alives of the
+	// MinIO client, for listing large buckets over latent links.
+	S3Transport TransportTuning
+
+	// ListPageSize sets the max keys of each object listing request,
+	// zero uses the MinIO client default.
+	ListPageSize int
+
+	// ContentTypeStatConcurrency bounds the objects stated at once to
+	// filter a listing by ContentTypes, zero uses
+	// DefaultContentTypeStatConcurrency.
+	ContentTypeStatConcurrency int
+
+	// InjectorLabels are added to every injector pod, merged with
+	// and overridden by the labels of a request.
+	InjectorLabels map[string]string
+
+	// InjectorAnnotations are added to every injector pod. When nil,
+	// DefaultInjectorAnnotations disable service mesh sidecars, which
+	// never exit and keep injector Jobs from completing.
+	InjectorAnnotations map[string]string
+
+	// InjectorTransferRetries is the number of times a failed copy is
+	// retried. InjectorTransferTimeout is the seconds of I/O inactivity
+	// after which rclone and awscli retry a request; mc has no such
+	// timeout and ignores it. Zero values keep the tools' defaults.
+	InjectorTransferRetries int
+	InjectorTransferTimeout int64
+
+	// InjectorHostAliases, InjectorDNSPolicy and InjectorDNSConfig set
+	// the hosts file entries and DNS resolution of every injector pod.
+	// Empty values leave the cluster defaults.
+	InjectorHostAliases []coreV1.HostAlias
+	InjectorDNSPolicy   coreV1.DNSPolicy
+	InjectorDNSConfig   *coreV1.PodDNSConfig
+
+	// Publisher receives create and delete lifecycle events, the
+	// CallbackPayload on "<PublishSubject>.<operation>". Nil disables
+	// publishing.
+	Publisher      Publisher
+	PublishSubject string
+
+	// K8sBreaker is the circuit breaker wrapping the transport of Cs,
+	// reported by Ready. Nil when Cs is not wrapped.
+	K8sBreaker *K8sBreaker
+
+	// PushgatewayURL receives the metrics of every completed create,
+	// for short-lived invocations that are never scraped. Pushes are
+	// grouped by PushgatewayJob (default Service) and
+	// PushgatewayInstance (default the hostname). Empty disables
+	// pushing.
+	PushgatewayURL      string
+	PushgatewayJob      string
+	PushgatewayInstance string
+
+	// ReadOnly starts the API rejecting mutating requests, toggled
+	// at runtime through the admin /read-only endpoint.
+	ReadOnly bool
+
+	// ReadyRoot makes the root handler respond 503 while PVCI is not
+	// Ready, for load balancers probing "/".
+	ReadyRoot bool
+
+	// AdminToken is the bearer token of admin endpoints, which are
+	// disabled when empty.
+	AdminToken string
+
+	// FailOnCountMismatch fails a create whose injector landed fewer
+	// files than the objects sized, rather than completing it with a
+	// COUNT_MISMATCH warning.
+	FailOnCountMismatch bool
+
+	// MCConfigSecret passes the mc injector its credentials in an mc
+	// config Secret mounted as --config-dir, rather than in the
+	// MC_HOST_objstore environment variable of the pod spec.
+	MCConfigSecret bool
+
+	// ForceReplaceTerminating clears the finalizers of a PVCI managed
+	// PVC stuck in Terminating that blocks a create.
+	ForceReplaceTerminating bool
+
+	// StallTimeout fails an injector Job whose source volume usage
+	// does not grow for the duration, zero disables stall detection.
+	// With stall detection, a transfer still growing is allowed past
+	// its size-derived timeout.
+	StallTimeout time.Duration
+
+	// MaxInjectorDuration is the hard ceiling on an injector Job's run
+	// time, set as its activeDeadlineSeconds. Zero leaves progressing
+	// transfers unbounded.
+	MaxInjectorDuration time.Duration
+
+	// CloneRetries retries a failed create or bind wait of the final
+	// clone PVC, after the transfer completed, with a backoff starting
+	// at CloneRetryInterval seconds.
+	CloneRetries int
+
+	// FinalizerPatchRetries retries removing the finalizer of the
+	// deleted source PVC once a create completes. A source PVC still
+	// held after the retries is reported as a warning of the create
+	// and left for Reconcile.
+	FinalizerPatchRetries int
+
+	// ReclaimOrphanedSource deletes a PVCI managed source PVC left by
+	// a failed create that blocks a retry, unless its injector Job is
+	// still running.
+	ReclaimOrphanedSource bool
+
+	// AdoptInjectorJobs waits on a running PVCI managed injector Job
+	// of the volume found by a create, and replaces a finished one,
+	// rather than failing the create.
+	AdoptInjectorJobs bool
+
+	// TraceHeader names the request header carrying a trace context,
+	// stamped on created resources and logs. Defaults to
+	// DefaultTraceHeader (W3C traceparent).
+	TraceHeader string
+
+	// CreateConcurrency bounds the async creates running at once,
+	// holding up to CreateQueueSize more waiting for a worker. Zero
+	// runs every async create immediately.
+	CreateConcurrency int
+	CreateQueueSize   int
+
+	// FastStartSize is the initial source PVC request in bytes of
+	// a fast start create, zero uses DefaultFastStartSize.
+	FastStartSize int64
+
+	// ReconcileTTL is the age past which Reconcile deletes finished
+	// injector Jobs and orphaned source PVCs, zero uses
+	// DefaultReconcileTTL.
+	ReconcileTTL time.Duration
+
+	// JobSweepAge is the age past which SweepJobs deletes finished
+	// injector Jobs, zero uses DefaultJobSweepAge.
+	JobSweepAge time.Duration
+
+	// AutoGrowThreshold is the percent of capacity used at which
+	// AutoGrow expands a PVC, zero uses DefaultAutoGrowThreshold.
+	AutoGrowThreshold int
+
+	// AutoGrowPercent is the percent AutoGrow grows a PVC's storage
+	// request by, zero uses DefaultAutoGrowPercent.
+	AutoGrowPercent int
+
+	// SyncCreateMaxSeconds is the longest timeout of a create run by
+	// /create; longer creates are run asynchronously, as by
+	// /create-async. Zero, the default, runs every create
+	// synchronously.
+	SyncCreateMaxSeconds int64
+
+	// ManyObjectsThreshold is the object count at which mc injectors
+	// mirror instead of recursively copying, zero disables mirroring.
+	// ManyObjectsOPS is the objects per second estimated for mirrors,
+	// zero uses DefaultManyObjectsOPS.
+	ManyObjectsThreshold int64
+	ManyObjectsOPS       int
+
+	// MetricLabels are the keys of request metric labels added to the
+	// labels of create metrics. Request labels never reach metrics.
+	MetricLabels []string
+
+	// MetricLabelMaxValues bounds the distinct values of each metric
+	// label, zero uses DefaultMetricLabelMaxValues.
+	MetricLabelMaxValues int
+
+	// OperationRetention is how long completed creates are listed by
+	// /operations, zero uses DefaultOperationRetention.
+	OperationRetention time.Duration
+
+	// Recorder records Kubernetes Events on PVCs. When nil, NewApi
+	// creates one writing through Cs.
+	Recorder record.EventRecorder
+
+	Log *zap.Logger
+	Cs  kubernetes.Interface
 }
 
 // API is primary object implementing the core API methods
 // and HTTP handlers
 type API struct {
 	*Config
-	LogErrors prometheus.Counter
+	LogErrors  prometheus.Counter
+	sizeCache  *sizeCache
+	scCache    *storageClassCache
+	createPool *createPool
+	readOnly   *readOnly
+	progress   *progressHistory
+	operations *operations
+
+	metricLabelValues *metricLabelValues
+	createsTotal      *prometheus.CounterVec
+
+	// tunablesMu guards the Config fields updated by SetTunables
+	tunablesMu *sync.RWMutex
+
+	capabilities Capabilities
+}
+
+// DefaultInjectorAnnotations disable Istio and Linkerd sidecar
+// injection for injector pods.
+var DefaultInjectorAnnotations = map[string]string{
+	"sidecar.istio.io/inject": "false",
+	"linkerd.io/inject":       "disabled",
 }
 
 // NewApi constructs an API object and populates it with
 // configuration along with setting defaults where required.
 func NewApi(cfg *Config) (*API, error) {
-	a := &API{Config: cfg}
+	a := &API{
+		Config:     cfg,
+		sizeCache:  newSizeCache(),
+		scCache:    newStorageClassCache(),
+		readOnly:   &readOnly{},
+		progress:   newProgressHistory(),
+		tunablesMu: &sync.RWMutex{},
+	}
+	a.readOnly.set(cfg.ReadOnly)
 
 	// default logger if none specified
 	if a.Log == nil {
@@ -106,13 +721,232 @@ func NewApi(cfg *Config) (*API, error) {
 		a.Log = logger
 	}
 
+	a.createPool = newCreatePool(a.CreateConcurrency, a.CreateQueueSize, a.Log)
+
+	// default namespace for requests that omit one
+	if a.DefaultNamespace == "" {
+		a.DefaultNamespace = "default"
+	}
+
+	if a.SystemNamespace == "" {
+		a.SystemNamespace = a.DefaultNamespace
+	}
+
+	if a.InjectorAnnotations == nil {
+		a.InjectorAnnotations = DefaultInjectorAnnotations
+	}
+
+	if a.RcloneImage == "" {
+		a.RcloneImage = DefaultRcloneImage
+	}
+
+	if a.AWSCLIImage == "" {
+		a.AWSCLIImage = DefaultAWSCLIImage
+	}
+
+	if a.VerifyImage == "" {
+		a.VerifyImage = DefaultVerifyImage
+	}
+
+	if a.MaxBodySize == 0 {
+		a.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if a.TraceHeader == "" {
+		a.TraceHeader = DefaultTraceHeader
+	}
+
+	if a.FastStartSize == 0 {
+		a.FastStartSize = DefaultFastStartSize
+	}
+
+	if a.ContentTypeStatConcurrency <= 0 {
+		a.ContentTypeStatConcurrency = DefaultContentTypeStatConcurrency
+	}
+
+	if a.PublishSubject == "" {
+		a.PublishSubject = DefaultPublishSubject
+	}
+
+	if a.PushgatewayJob == "" {
+		a.PushgatewayJob = a.Service
+	}
+	if a.PushgatewayInstance == "" {
+		a.PushgatewayInstance, _ = os.Hostname()
+	}
+
+	if a.ReconcileTTL == 0 {
+		a.ReconcileTTL = DefaultReconcileTTL
+	}
+
+	if a.JobSweepAge == 0 {
+		a.JobSweepAge = DefaultJobSweepAge
+	}
+
+	if a.ManyObjectsOPS == 0 {
+		a.ManyObjectsOPS = DefaultManyObjectsOPS
+	}
+
+	if a.AutoGrowThreshold == 0 {
+		a.AutoGrowThreshold = DefaultAutoGrowThreshold
+	}
+
+	if a.AutoGrowPercent == 0 {
+		a.AutoGrowPercent = DefaultAutoGrowPercent
+	}
+
+	if a.OperationRetention == 0 {
+		a.OperationRetention = DefaultOperationRetention
+	}
+	a.operations = newOperations(a.OperationRetention)
+
+	// create metrics labelled by the configured metric labels
+	err := checkMetricLabelNames(a.MetricLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.MetricLabelMaxValues == 0 {
+		a.MetricLabelMaxValues = DefaultMetricLabelMaxValues
+	}
+	a.metricLabelValues = newMetricLabelValues(a.MetricLabelMaxValues)
+	a.createsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pvci_creates_total",
+		Help: "Number of completed PVCI creates.",
+	}, append([]string{"namespace", "outcome"}, a.MetricLabels...))
+
+	// record Kubernetes Events on PVCs
+	if a.Recorder == nil {
+		a.Recorder = a.newEventRecorder()
+	}
+
+	// default label scheme
+	if a.LabelPrefix == "" {
+		a.LabelPrefix = "pvci.txn2.com"
+	}
+
+	// detect volume cloning support
+	a.capabilities = a.probeCapabilities()
+
+	// fail fast on an unsupported mc image
+	err = a.checkMCImage()
+	if err != nil {
+		return nil, err
+	}
+
 	return a, nil
 }
 
+// resolveNamespace sets the namespace of a PVCRequestConfig to the
+// configured DefaultNamespace when omitted and ensures the resulting
+// namespace is permitted by AllowedNamespaces (an empty list allows any).
+func (a *API) resolveNamespace(pvcRequestConfig *PVCRequestConfig) error {
+	if pvcRequestConfig.Namespace == "" {
+		pvcRequestConfig.Namespace = a.DefaultNamespace
+	}
+
+	if len(a.AllowedNamespaces) == 0 {
+		return nil
+	}
+
+	for _, ns := range a.AllowedNamespaces {
+		if ns == pvcRequestConfig.Namespace {
+			return nil
+		}
+	}
+
+	return forbidden("namespace %s is not allowed", pvcRequestConfig.Namespace)
+}
+
+// labelKey returns a label key in the configured LabelPrefix scheme.
+func (a *API) labelKey(key string) string {
+	return a.LabelPrefix + "/" + key
+}
+
+// volLabels returns the labels stamped on every resource PVCI creates
+// for a volume. Status and delete rely on these to find and verify
+// PVCI managed resources.
+func (a *API) volLabels(volName string) map[string]string {
+	return map[string]string{
+		a.labelKey("vol"):     volName,
+		a.labelKey("service"): a.Service,
+		a.labelKey("version"): a.Version,
+	}
+}
+
+// legacyServiceLabel is the service label of PVCs created before the
+// vol label and LabelPrefix.
+const legacyServiceLabel = "pvci.txn2.com/service"
+
+// managedPVC reports whether a PVC is the PVCI volume volName. PVCs
+// created before the vol label carry only the legacy service label and
+// the origin annotation, and are recognised by those.
+func (a *API) managedPVC(pvc *coreV1.PersistentVolumeClaim, volName string) bool {
+	if vol, ok := pvc.Labels[a.labelKey("vol")]; ok {
+		return vol == volName
+	}
+
+	return pvc.Name == volName && pvc.Labels[legacyServiceLabel] == a.Service &&
+		pvc.Annotations["pvci.txn2.com/origin"] != ""
+}
+
+// injectorLabels returns the labels stamped on injector Jobs and pods.
+func (a *API) injectorLabels(volName string) map[string]string {
+	labels := a.volLabels(volName)
+	labels[a.labelKey("job")] = "injector"
+
+	return labels
+}
+
+// injectorPodLabels returns the labels of an injector pod, merging the
+// configured and requested custom labels with PVCI's reserved labels.
+func (a *API) injectorPodLabels(pvcRequestConfig PVCRequestConfig) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for _, custom := range []map[string]string{a.InjectorLabels, pvcRequestConfig.Labels} {
+		for k, v := range custom {
+			if strings.HasPrefix(k, a.LabelPrefix+"/") {
+				return nil, badRequest("label %s is reserved", k)
+			}
+
+			if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+				return nil, badRequest("invalid label key %s: %s", k, strings.Join(errs, ", "))
+			}
+
+			if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+				return nil, badRequest("invalid label value %s: %s", v, strings.Join(errs, ", "))
+			}
+
+			labels[k] = v
+		}
+	}
+
+	for k, v := range a.injectorLabels(pvcRequestConfig.Name) {
+		labels[k] = v
+	}
+
+	return labels, nil
+}
+
+// injectorSelector returns a label selector matching the injector
+// Jobs and pods of a volume.
+func (a *API) injectorSelector(volName string) string {
+	return fmt.Sprintf("%s=%s,%s=injector", a.labelKey("vol"), volName, a.labelKey("job"))
+}
+
 // OkHandler is provided for created a default slash route for the
-// HTTP API and returns basic version, node and service name.
+// HTTP API and returns basic version, node and service name. With
+// Config.ReadyRoot, it responds 503 while PVCI is not Ready.
 func (a *API) OkHandler(version string, mode string, service string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if a.ReadyRoot {
+			if err := a.Ready(); err != nil {
+				code, status := ErrorStatus(err)
+				c.JSON(status, gin.H{"version": version, "mode": mode, "service": service, "error": err.Error(), "code": code})
+				return
+			}
+		}
+
 		c.JSON(http.StatusOK, gin.H{"version": version, "mode": mode, "service": service})
 	}
 }
@@ -123,17 +957,13 @@ func (a *API) DeleteHandler() gin.HandlerFunc {
 
 		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "unable to read post body",
-			})
+			a.abortWithParseError(c, err)
 			return
 		}
 
 		err = a.Delete(*pvcRequestConfig)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			a.abortWithError(c, err)
 			return
 		}
 
@@ -141,17 +971,37 @@ func (a *API) DeleteHandler() gin.HandlerFunc {
 	}
 }
 
-// Delete a PVC. @TODO limit to pvc created by PCI by looking at labels
+// Delete a PVC created by PVCI. PVCs not carrying the PVCI volume
+// label for the requested name are refused.
 func (a *API) Delete(pvcRequestConfig PVCRequestConfig) error {
 	ctx := context.Background()
+	a = a.withTrace(pvcRequestConfig)
+
+	err := a.resolveNamespace(&pvcRequestConfig)
+	if err != nil {
+		return err
+	}
 
 	pvcClient := a.Cs.CoreV1().PersistentVolumeClaims(pvcRequestConfig.Namespace)
 
-	err := pvcClient.Delete(ctx, pvcRequestConfig.Name, metaV1.DeleteOptions{})
+	pvc, err := pvcClient.Get(ctx, pvcRequestConfig.Name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !a.managedPVC(pvc, pvcRequestConfig.Name) {
+		return forbidden("PVC %s is not managed by %s", pvcRequestConfig.Name, a.Service)
+	}
+
+	err = pvcClient.Delete(ctx, pvcRequestConfig.Name, metaV1.DeleteOptions{})
 	if err != nil {
 		return err
 	}
 
+	a.event(pvc, EventDeleted, "Deletion requested through %s", a.Service)
+
+	a.notify("delete", pvcRequestConfig, nil)
+
 	return nil
 }
 
@@ -162,17 +1012,13 @@ func (a *API) GetStatusHandler() gin.HandlerFunc {
 
 		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "unable to read post body",
-			})
+			a.abortWithParseError(c, err)
 			return
 		}
 
 		sr, err := a.GetStatus(*pvcRequestConfig)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			a.abortWithError(c, err)
 			return
 		}
 
@@ -186,30 +1032,95 @@ func (a *API) GetStatus(pvcRequestConfig PVCRequestConfig) (StatusReport, error)
 	sr := StatusReport{}
 	ctx := context.Background()
 
+	err := a.resolveNamespace(&pvcRequestConfig)
+	if err != nil {
+		return sr, err
+	}
+
 	// get injector status
 	podClient := a.Cs.CoreV1().Pods(pvcRequestConfig.Namespace)
 
 	pods, err := podClient.List(ctx, metaV1.ListOptions{
-		LabelSelector: fmt.Sprintf("pvci.txn2.com/vol=%s", pvcRequestConfig.Name),
+		LabelSelector: a.injectorSelector(pvcRequestConfig.Name),
 	})
+	if err != nil || pods == nil {
+		sr.setInjectorStatus(nil, err)
+	} else {
+		sr.setInjectorStatus(pods.Items, nil)
+	}
+
+	// get pvc status
+	pvcClient := a.Cs.CoreV1().PersistentVolumeClaims(pvcRequestConfig.Namespace)
+
+	pvc, err := pvcClient.Get(ctx, pvcRequestConfig.Name, metaV1.GetOptions{})
+	sr.setPVCStatus(pvc, err)
+
+	if err == nil && pvc.Status.Phase == coreV1.ClaimPending {
+		event, _ := a.provisioningEvent(pvc)
+		if event != nil {
+			sr.PVCProvisioning = fmt.Sprintf("%s: %s", event.Reason, event.Message)
+		}
+	}
+
+	sr.Progress = a.progress.get(pvcRequestConfig.Namespace, fmt.Sprintf("%s-injector", pvcRequestConfig.Name))
+
+	return sr, nil
+}
+
+// setInjectorStatus populates the injector fields of a StatusReport
+// from the injector pods of a volume or the error listing them.
+func (sr *StatusReport) setInjectorStatus(pods []coreV1.Pod, err error) {
 	if err != nil {
 		sr.InjectorHasError = true
 		sr.InjectorError = err.Error()
 	}
 
-	if pods == nil || len(pods.Items) < 1 {
+	if len(pods) < 1 {
 		sr.InjectorHasError = true
 		sr.InjectorError = "no injectors found"
+		return
 	}
 
-	if pods != nil && len(pods.Items) > 0 {
-		sr.InjectorState = fmt.Sprintf("%s", pods.Items[0].Status.Phase)
+	sr.InjectorPods = make([]InjectorPod, 0, len(pods))
+	for _, pod := range pods {
+		sr.InjectorPods = append(sr.InjectorPods, InjectorPod{
+			Name:                  pod.Name,
+			Node:                  pod.Spec.NodeName,
+			Phase:                 pod.Status.Phase,
+			Created:               pod.CreationTimestamp.Time,
+			InitContainerStatuses: pod.Status.InitContainerStatuses,
+			ContainerStatuses:     pod.Status.ContainerStatuses,
+		})
 	}
 
-	// get pvc status
-	pvcClient := a.Cs.CoreV1().PersistentVolumeClaims(pvcRequestConfig.Namespace)
+	// creation times have second resolution, a retry created in the
+	// second its predecessor failed is the active attempt
+	sort.SliceStable(sr.InjectorPods, func(i, j int) bool {
+		pi, pj := sr.InjectorPods[i], sr.InjectorPods[j]
+		if !pi.Created.Equal(pj.Created) {
+			return pi.Created.After(pj.Created)
+		}
+		return !podFinished(pi.Phase) && podFinished(pj.Phase)
+	})
 
-	pvc, err := pvcClient.Get(ctx, pvcRequestConfig.Name, metaV1.GetOptions{})
+	sr.InjectorAttempts = len(sr.InjectorPods)
+	for _, pod := range sr.InjectorPods {
+		if pod.Phase == coreV1.PodFailed {
+			sr.InjectorFailedAttempts++
+		}
+	}
+
+	sr.InjectorState = fmt.Sprintf("%s", sr.InjectorPods[0].Phase)
+}
+
+// podFinished reports whether a pod phase is terminal.
+func podFinished(phase coreV1.PodPhase) bool {
+	return phase == coreV1.PodSucceeded || phase == coreV1.PodFailed
+}
+
+// setPVCStatus populates the PVC fields of a StatusReport from a PVC
+// or the error getting it.
+func (sr *StatusReport) setPVCStatus(pvc *coreV1.PersistentVolumeClaim, err error) {
 	if err != nil {
 		sr.PVCHasError = true
 		sr.PVCError = err.Error()
@@ -217,9 +1128,10 @@ func (a *API) GetStatus(pvcRequestConfig PVCRequestConfig) (StatusReport, error)
 
 	if pvc != nil {
 		sr.PVCStatus = pvc.Status
+		sr.PVName = pvc.Spec.VolumeName
+		sr.Warnings = Warnings(pvc)
+		sr.Ready = Ready(pvc)
 	}
-
-	return sr, nil
 }
 
 // GetSizeHandler used by the HTTP POST endpoint /size to get the
@@ -229,17 +1141,13 @@ func (a *API) GetSizeHandler() gin.HandlerFunc {
 
 		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "unable to read post body",
-			})
+			a.abortWithParseError(c, err)
 			return
 		}
 
 		cnt, sz, err := a.GetSize(*pvcRequestConfig)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			a.abortWithError(c, err)
 			return
 		}
 
@@ -248,8 +1156,42 @@ func (a *API) GetSizeHandler() gin.HandlerFunc {
 }
 
 // GetSize gets the size of a list of S3/MinIO objects (files) based on
-// bucket and prefix specified in a PVCRequestConfig object.
+// bucket and prefix specified in a PVCRequestConfig object. When the
+// SizeCacheTTL is set, results are cached unless RefreshSize is requested.
 func (a *API) GetSize(pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
+	err := checkListCredentials(pvcRequestConfig.S3Config)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = checkContentTypes(pvcRequestConfig)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if a.SizeCacheTTL <= 0 {
+		return a.getSize(pvcRequestConfig)
+	}
+
+	key := sizeCacheKey(pvcRequestConfig.S3Config)
+	if !pvcRequestConfig.RefreshSize {
+		if objCount, sz, ok := a.sizeCache.get(key); ok {
+			return objCount, sz, nil
+		}
+	}
+
+	objCount, sz, err := a.getSize(pvcRequestConfig)
+	if err != nil {
+		return objCount, sz, err
+	}
+
+	a.sizeCache.set(key, objCount, sz, a.SizeCacheTTL)
+
+	return objCount, sz, nil
+}
+
+// getSize implements GetSize without the size cache.
+func (a *API) getSize(pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
 	objCount := int64(0)
 	totalSize := int64(0)
 
@@ -258,6 +1200,63 @@ func (a *API) GetSize(pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
 		return objCount, totalSize, err
 	}
 
+	switch pvcRequestConfig.SizeSource {
+	case "", SizeSourceList:
+	case SizeSourceInventory:
+		return a.getSizeFromInventory(minioClient, pvcRequestConfig)
+	default:
+		return objCount, totalSize, badRequest("unknown size_source %s", pvcRequestConfig.SizeSource)
+	}
+
+	if pvcRequestConfig.S3AsOf != "" {
+		return a.getSizeAsOf(minioClient, pvcRequestConfig)
+	}
+
+	return a.listSize(minioClient, pvcRequestConfig)
+}
+
+// listSize gets the count and size of the latest objects under the
+// bucket and prefix of a PVCRequestConfig, failing with KEY_COLLISION
+// for keys that are also directories of other keys unless
+// AllowKeyCollisions is set.
+func (a *API) listSize(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
+	objCount := int64(0)
+	totalSize := int64(0)
+
+	collisions := &keyCollisions{delimiter: pvcRequestConfig.delimiter()}
+
+	err := a.listObjects(minioClient, pvcRequestConfig, func(object minio.ObjectInfo) error {
+		objCount += 1
+		totalSize += object.Size
+		collisions.add(object.Key)
+		return nil
+	})
+	if err != nil {
+		return objCount, totalSize, err
+	}
+
+	// fail before a copy would
+	if !pvcRequestConfig.AllowKeyCollisions {
+		err = collisions.err()
+	}
+
+	return objCount, totalSize, err
+}
+
+// listObjects calls fn for each of the latest objects under the bucket
+// and prefix of a PVCRequestConfig, stopping at the first error. When
+// ListPageSize is configured, objects are listed in pages of that many
+// keys, otherwise the MinIO client's default paging is used. Objects
+// not matching the ContentTypes filter are skipped.
+func (a *API) listObjects(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig, fn func(object minio.ObjectInfo) error) error {
+	if len(pvcRequestConfig.ContentTypes) > 0 {
+		return a.listContentTypes(minioClient, pvcRequestConfig, fn)
+	}
+
+	if a.ListPageSize > 0 {
+		return a.listObjectPages(minioClient, pvcRequestConfig, fn)
+	}
+
 	// Create a done channel to control 'ListObjectsV2' go routine.
 	doneCh := make(chan struct{})
 
@@ -273,85 +1272,483 @@ func (a *API) GetSize(pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
 	for object := range objectCh {
 		if object.Err != nil {
 			a.Log.Warn("object error", zap.Error(object.Err))
-			return objCount, totalSize, object.Err
+			return object.Err
+		}
+
+		err := fn(object)
+		if err != nil {
+			return err
 		}
-		objCount += 1
-		totalSize += object.Size
 	}
 
-	return objCount, totalSize, nil
+	return nil
 }
 
-// CreatePVCHandler used by the HTTP POST /create endpoint. CreatePVCHandler is
-// the core purpose of PVCI, to create PVCs and inject them with files. This
-// handler expects a JSON object representing a PVCRequestConfig.
-func (a *API) CreatePVCHandler() gin.HandlerFunc {
-	return func(c *gin.Context) {
+// listObjectPages implements listObjects with ListPageSize max keys
+// per ListObjectsV2 request.
+func (a *API) listObjectPages(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig, fn func(object minio.ObjectInfo) error) error {
+	core := minio.Core{Client: minioClient}
+	continuationToken := ""
 
-		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+	for {
+		result, err := core.ListObjectsV2(
+			pvcRequestConfig.S3Bucket,
+			pvcRequestConfig.S3Prefix,
+			continuationToken,
+			false,
+			"",
+			a.ListPageSize,
+			"",
+		)
 		if err != nil {
-			a.Log.Warn("parsePVCRequestConfig aborted with error",
-				zap.Int("code", http.StatusBadRequest),
-				zap.String("reason", err.Error()))
+			a.Log.Warn("object error", zap.Error(err))
+			return err
+		}
 
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "unable to read post body",
-			})
-			return
+		for _, object := range result.Contents {
+			err = fn(object)
+			if err != nil {
+				return err
+			}
 		}
 
-		err = a.CreatePVC(*pvcRequestConfig)
+		if !result.IsTruncated {
+			return nil
+		}
+
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Estimate describes the objects of a PVCRequestConfig and the time
+// PVCI expects and allows for injecting them.
+type Estimate struct {
+	Objects            int64  `json:"objects"`
+	Bytes              int64  `json:"bytes"`
+	RunEstimateSeconds int64  `json:"run_estimate_seconds"`
+	TimeoutSeconds     int64  `json:"timeout_seconds"`
+	CopyStrategy       string `json:"copy_strategy"`
+}
+
+// EstimateHandler used by the HTTP POST /estimate endpoint returns an
+// Estimate for a PVCRequestConfig as JSON.
+func (a *API) EstimateHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
 		if err != nil {
-			a.Log.Warn("CreatePVCHandler aborted with error",
-				zap.Int("code", http.StatusBadRequest),
-				zap.String("reason", err.Error()))
+			a.abortWithParseError(c, err)
+			return
+		}
 
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+		est, err := a.Estimate(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{})
+		c.JSON(http.StatusOK, est)
 	}
 }
 
-func (a *API) CreatePVCAsyncHandler() gin.HandlerFunc {
+// Estimate sizes the objects of a PVCRequestConfig and returns the run
+// estimate and injector timeout CreatePVC would use, without
+// provisioning anything. Clients polling /status after /create-async
+// can use TimeoutSeconds as a deadline.
+func (a *API) Estimate(pvcRequestConfig PVCRequestConfig) (Estimate, error) {
+	est := Estimate{}
+
+	// a requested size replaces the measured one, as in CreatePVC
+	objCount, sz := int64(0), pvcRequestConfig.RequestedSize
+	if !pvcRequestConfig.SkipSizeCompute || pvcRequestConfig.RequestedSize <= 0 {
+		var err error
+		objCount, sz, err = a.GetSize(pvcRequestConfig)
+		if err != nil {
+			return est, err
+		}
+
+		if pvcRequestConfig.RequestedSize > 0 {
+			sz = pvcRequestConfig.RequestedSize
+		}
+	}
+
+	strategy := a.copyStrategy(pvcRequestConfig, objCount)
+	runEst := a.transferEstimate(pvcRequestConfig, strategy, objCount, sz)
+
+	est.Objects = objCount
+	est.Bytes = sz
+	est.RunEstimateSeconds = runEst
+	est.TimeoutSeconds = jobTimeout(runEst)
+	est.CopyStrategy = strategy
+
+	return est, nil
+}
+
+// CreatePVCHandler used by the HTTP POST /create endpoint. CreatePVCHandler is
+// the core purpose of PVCI, to create PVCs and inject them with files. This
+// handler expects a JSON object representing a PVCRequestConfig.
+func (a *API) CreatePVCHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
 		if err != nil {
-			a.Log.Warn("parsePVCRequestConfig aborted with error",
-				zap.Int("code", http.StatusBadRequest),
-				zap.String("reason", err.Error()))
+			a.abortWithParseError(c, err)
+			return
+		}
 
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "unable to read post body",
-			})
+		// the response reads back the created PVC by its namespace
+		err = a.resolveNamespace(pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
 			return
 		}
 
-		go func() {
-			err = a.CreatePVC(*pvcRequestConfig)
+		// creates outlasting the server's write timeout would drop
+		// the connection, run them as with /create-async instead
+		if a.SyncCreateMaxSeconds > 0 {
+			est, err := a.Estimate(*pvcRequestConfig)
 			if err != nil {
-				a.Log.Warn("CreatePVCHandler aborted with error",
-					zap.Int("code", http.StatusBadRequest),
-					zap.String("reason", err.Error()))
+				a.abortWithError(c, err)
+				return
+			}
+
+			pvcRequestConfig.Estimate = &est
+
+			if est.TimeoutSeconds > a.SyncCreateMaxSeconds {
+				err = a.submitCreate(*pvcRequestConfig)
+				if err != nil {
+					a.abortWithError(c, err)
+					return
+				}
+
+				c.JSON(http.StatusAccepted, gin.H{
+					"async":    true,
+					"estimate": est,
+					"reason": fmt.Sprintf("create may take up to %ds, longer than the %ds allowed synchronous creates; poll /status for its result",
+						est.TimeoutSeconds, a.SyncCreateMaxSeconds),
+				})
+				return
 			}
-		}()
+		}
+
+		err = a.CreatePVC(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		// report discrepancies between the objects sized and landed
+		pvc, err := a.getPVC(pvcRequestConfig.Namespace, pvcRequestConfig.Name)
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"verification": VerificationFromPVC(pvc),
+			"pv_name":      pvc.Spec.VolumeName,
+			"warnings":     Warnings(pvc),
+		})
+	}
+}
+
+func (a *API) CreatePVCAsyncHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		err = a.submitCreate(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{})
 	}
 }
 
+// submitCreate runs a create on the create pool, logging its failure.
+func (a *API) submitCreate(pvcRequestConfig PVCRequestConfig) error {
+	return a.createPool.submit(func() {
+		err := a.CreatePVC(pvcRequestConfig)
+		if err != nil {
+			code, status := ErrorStatus(err)
+			a.Log.Warn("async create failed",
+				zap.Int("status", status),
+				zap.String("code", code),
+				zap.String("reason", err.Error()))
+		}
+	})
+}
+
+// awaitCreate runs a create on the create pool, returning a channel
+// receiving its result. Results nobody waits for any more are logged.
+func (a *API) awaitCreate(pvcRequestConfig PVCRequestConfig) (<-chan error, error) {
+	done := make(chan error, 1)
+
+	err := a.createPool.submit(func() {
+		err := a.CreatePVC(pvcRequestConfig)
+		if err != nil {
+			code, status := ErrorStatus(err)
+			a.Log.Warn("awaited create failed",
+				zap.Int("status", status),
+				zap.String("code", code),
+				zap.String("reason", err.Error()))
+		}
+		done <- err
+	})
+
+	return done, err
+}
+
+// CreatePVCWaitHandler used by the HTTP POST /create-wait endpoint. The
+// create is started on the create pool and the handler blocks until it
+// completes or the deadline given by the timeout query parameter (e.g.
+// ?timeout=90s or ?timeout=90) expires. In either case the current
+// StatusReport is returned along with whether the create is still
+// running or queued.
+func (a *API) CreatePVCWaitHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		timeout, err := parseTimeout(c.DefaultQuery("timeout", "60s"))
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		pvcRequestConfig, err := a.parsePVCRequestConfig(c)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		done, err := a.awaitCreate(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		running := false
+		var createErr error
+
+		select {
+		case createErr = <-done:
+		case <-time.After(timeout):
+			running = true
+		}
+
+		sr, err := a.GetStatus(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		if createErr != nil {
+			code, status := ErrorStatus(createErr)
+			a.Log.Warn("CreatePVCWaitHandler create failed",
+				zap.Int("status", status),
+				zap.String("code", code),
+				zap.String("reason", createErr.Error()))
+
+			c.JSON(status, gin.H{"running": running, "error": createErr.Error(), "code": code, "status": sr})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"running": running, "error": "", "status": sr})
+	}
+}
+
+// parseTimeout parses a duration (e.g. "90s", "5m") or a plain integer
+// number of seconds.
+func parseTimeout(value string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(value); err == nil {
+		value = fmt.Sprintf("%ds", secs)
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, badRequest("invalid timeout %s", value)
+	}
+
+	return timeout, nil
+}
+
 // CreatePVC is the core purpose of PVCI, to create PVCs and inject
 // them with files. CreatePVC takes a PVCRequestConfig object and
 // creates a Kubernetes PVC, followed by a Kubernetes Job used to
-// populate it.
+// populate it. The request's callback is notified of the result.
 func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
+	a = a.withTrace(pvcRequestConfig)
+
+	err := a.resolveNamespace(&pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	inFlight := createsInFlight.WithLabelValues(pvcRequestConfig.Namespace)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	op := a.operations.start("create", pvcRequestConfig.Namespace, pvcRequestConfig.Name)
+
+	err = a.recoveredCreatePVC(pvcRequestConfig)
+	if err == nil {
+		// consumers gate on the annotation rather than the PVC
+		// existing or binding, which precede the transfer
+		err = a.markReady(pvcRequestConfig.Namespace, pvcRequestConfig.Name)
+		if err != nil {
+			a.Log.Error("unable to annotate PVC ready",
+				zap.String("name", pvcRequestConfig.Name),
+				zap.String("namespace", pvcRequestConfig.Namespace),
+				zap.Error(err),
+			)
+		}
+	}
+	done := a.operations.finish(op, err)
+	a.countCreate(done, pvcRequestConfig.MetricLabels)
+	a.pushMetrics(done, pvcRequestConfig.MetricLabels)
+	a.notify("create", pvcRequestConfig, err)
+
+	return err
+}
+
+// recoveredCreatePVC runs createPVC, returning a panic as an INTERNAL
+// Error so a bad request fails its own create rather than the process.
+func (a *API) recoveredCreatePVC(pvcRequestConfig PVCRequestConfig) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			createPanics.Inc()
+			a.Log.Error("recovered panic in create",
+				zap.String("namespace", pvcRequestConfig.Namespace),
+				zap.String("name", pvcRequestConfig.Name),
+				zap.Any("panic", r),
+				zap.Stack("stack"),
+			)
+			err = newError(ErrCodeInternal, http.StatusInternalServerError, "create panicked: %v", r)
+		}
+	}()
+
+	return a.createPVC(pvcRequestConfig)
+}
+
+// createPVC implements CreatePVC for a PVCRequestConfig with a
+// resolved namespace.
+func (a *API) createPVC(pvcRequestConfig PVCRequestConfig) error {
 	ctx := context.Background()
 	api := a.Cs.CoreV1()
 
+	// validate the request before replacing or reclaiming existing
+	// PVCs, so a bad request leaves them as found
+	podLabels, err := a.injectorPodLabels(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.checkMetricLabels(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkTransport(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkCredentials(pvcRequestConfig.S3Config)
+	if err != nil {
+		return err
+	}
+
+	err = checkExtraVolumes(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.checkInjectorDNS(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkPartitionBy(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkContentTypes(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkShards(pvcRequestConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.checkStorageClasses(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.verifyStorageClasses(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkPopulator(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkAccessModes(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	err = checkVolumeStrategy(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.checkCrossClassClone(pvcRequestConfig.VolConfig)
+	if err != nil {
+		return err
+	}
+
+	// creates unable to clone may fall back to a direct inject
+	strategyWarning := ""
+	pvcRequestConfig.VolumeStrategy, strategyWarning = a.volumeStrategy(pvcRequestConfig.VolConfig)
+	direct := pvcRequestConfig.VolumeStrategy == VolumeStrategyDirectInject
+
+	if !direct {
+		err = a.checkCloneSupport(pvcRequestConfig.VolConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	// scale for known sparse (< 1) or expanding (> 1) data
+	sizeMultiplier := pvcRequestConfig.SizeMultiplier
+	if sizeMultiplier == 0 {
+		sizeMultiplier = 1
+	}
+	if sizeMultiplier < 0 {
+		return badRequest("size_multiplier must be greater than 0")
+	}
+
+	if pvcRequestConfig.RequestedSize < 0 {
+		return badRequest("requested_size must not be negative")
+	}
+	if pct := pvcRequestConfig.OveragePercent; pct != nil && (*pct < 0 || *pct > MaxOveragePercent) {
+		return badRequest("overage_pct must be between 0 and %d", MaxOveragePercent)
+	}
+	if pvcRequestConfig.SkipSizeCompute && pvcRequestConfig.RequestedSize == 0 {
+		return badRequest("skip_size_compute requires a requested_size")
+	}
+
 	// create a PersistentVolumeClaim sized for the bucket data
 	pvcClient := api.PersistentVolumeClaims(pvcRequestConfig.Namespace)
 
@@ -364,7 +1761,14 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 			zap.String("phase", fmt.Sprintf("%s", existingPVC.Status.Phase)),
 		)
 
-		return fmt.Errorf("found a %s PVC named %s", existingPVC.Status.Phase, pvcRequestConfig.Name)
+		replaced, err := a.replaceTerminatingPVC(existingPVC, pvcRequestConfig.Name)
+		if err != nil {
+			return err
+		}
+
+		if !replaced {
+			return conflict("found a %s PVC named %s", pvcPhase(existingPVC), pvcRequestConfig.Name)
+		}
 	}
 
 	// does the PVC exist
@@ -376,65 +1780,110 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 			zap.String("phase", fmt.Sprintf("%s", existingSrcPVC.Status.Phase)),
 		)
 
-		return fmt.Errorf("found a %s PVC named %s", existingSrcPVC.Status.Phase, existingSrcPVC.Name)
+		replaced, err := a.replaceTerminatingPVC(existingSrcPVC, pvcRequestConfig.Name)
+		if err != nil {
+			return err
+		}
+
+		if !replaced {
+			replaced, err = a.reclaimOrphanedSource(existingSrcPVC, pvcRequestConfig.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !replaced {
+			return conflict("found a %s PVC named %s", pvcPhase(existingSrcPVC), existingSrcPVC.Name)
+		}
 	}
 
+	// a fast start provisions the source PVC while the bucket is
+	// sized, resizing it once the size is known. Storage classes
+	// without volume expansion fall back to sizing first.
+	fastStart := pvcRequestConfig.FastStart && a.allowsExpansion(pvcRequestConfig.StorageClass)
+
 	// get bucket size
-	objCount, sz, err := a.GetSize(pvcRequestConfig)
-	if err != nil {
-		return err
+	sized := make(chan sizeResult, 1)
+	go func() {
+		if pvcRequestConfig.SkipSizeCompute {
+			sized <- sizeResult{size: pvcRequestConfig.RequestedSize}
+			return
+		}
+
+		if est := pvcRequestConfig.Estimate; est != nil {
+			sized <- sizeResult{objCount: est.Objects, size: est.Bytes}
+			return
+		}
+
+		objCount, sz, err := a.GetSize(pvcRequestConfig)
+		if pvcRequestConfig.RequestedSize > 0 {
+			sz = pvcRequestConfig.RequestedSize
+		}
+		sized <- sizeResult{objCount: objCount, size: sz, err: err}
+	}()
+
+	objCount := int64(0)
+	sz := int64(0)
+
+	storageQtyBuffer := resource.Quantity{}
+	storageQtyBuffer.Set(a.FastStartSize)
+
+	annotations := map[string]string{
+		"pvci.txn2.com/origin": fmt.Sprintf("%s/%s/%s",
+			pvcRequestConfig.S3Endpoint,
+			pvcRequestConfig.S3Bucket,
+			pvcRequestConfig.S3Prefix,
+		),
 	}
 
-	// calculate run estimate
-	runEst := sz / (int64(a.AvgMPS) * 1048576)
+	stampTrace(annotations, pvcRequestConfig)
 
-	// calculate timeouts at a slow 5mb/sec
-	a.Log.Info("CreatePVC called",
-		zap.Int64("object_count", objCount),
-		zap.Int64("size", sz),
-		zap.Int64("run_est", runEst),
-		zap.Int("run_est_cfg_mps", a.AvgMPS),
-		zap.String("name", pvcRequestConfig.Name),
-		zap.String("namespace", pvcRequestConfig.Namespace),
-		zap.String("bucket", pvcRequestConfig.S3Bucket),
-		zap.String("prefix", pvcRequestConfig.S3Prefix),
-		zap.String("s3_endpoint", pvcRequestConfig.S3Endpoint),
-		zap.Any("vol_config", pvcRequestConfig.VolConfig),
-	)
+	if !fastStart {
+		res := <-sized
+		if res.err != nil {
+			return res.err
+		}
 
-	volMode := coreV1.PersistentVolumeFilesystem
+		objCount, sz = res.objCount, res.size
+		storageQtyBuffer = a.storageRequest(pvcRequestConfig, sz, sizeMultiplier)
 
-	// MiB/MB Conversion plus % overage for copy buffers and set
-	// the copy buffer needed for moving objects.
-	pctOver := 1 + (float64(a.VolumeOveragePercent) / 100)
+		annotations["pvci.txn2.com/requested_size"] = strconv.FormatInt(sz, 10)
+		annotations["pvci.txn2.com/object_count"] = strconv.FormatInt(objCount, 10)
+	}
 
-	storageQtyBuffer := resource.Quantity{}
-	storageQtyBuffer.Set(int64(math.Ceil((float64(sz) * 1.048576) * pctOver)))
+	// a volume populator fills the final PVC itself
+	if pvcRequestConfig.Populator != nil {
+		a.operations.setSize(pvcRequestConfig.Namespace, pvcRequestConfig.Name, objCount, sz)
+		return a.createPopulatedPVC(pvcRequestConfig, objCount, sz, storageQtyBuffer, annotations)
+	}
+
+	volMode := coreV1.PersistentVolumeFilesystem
+
+	// label a source PVC retained for debugging so cleanup
+	// tooling knows it is intentional
+	srcLabels := a.volLabels(pvcRequestConfig.Name)
+	if pvcRequestConfig.DebugKeepSource {
+		srcLabels[a.labelKey("debug-retained")] = "true"
+	}
 
 	srcPVCName := fmt.Sprintf("%s-src", pvcRequestConfig.Name)
 
+	// a direct inject's source PVC is the final PVC
+	if direct {
+		srcPVCName = pvcRequestConfig.Name
+	}
+
 	// Create source PVC Spec
 	srcPVCSpecification := coreV1.PersistentVolumeClaim{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:      srcPVCName,
-			Namespace: pvcRequestConfig.Namespace,
-			Labels: map[string]string{
-				"pvci.txn2.com/service": a.Service,
-				"pvci.txn2.com/version": a.Version,
-			},
-			Annotations: map[string]string{
-				"pvci.txn2.com/requested_size": strconv.FormatInt(sz, 10),
-				"pvci.txn2.com/object_count":   strconv.FormatInt(objCount, 10),
-				"pvci.txn2.com/origin": fmt.Sprintf("%s/%s/%s",
-					pvcRequestConfig.S3Endpoint,
-					pvcRequestConfig.S3Bucket,
-					pvcRequestConfig.S3Prefix,
-				),
-			},
+			Name:        srcPVCName,
+			Namespace:   pvcRequestConfig.Namespace,
+			Labels:      srcLabels,
+			Annotations: annotations,
 		},
 		Spec: coreV1.PersistentVolumeClaimSpec{
 			AccessModes: []coreV1.PersistentVolumeAccessMode{
-				"ReadWriteOnce",
+				pvcRequestConfig.srcAccessMode(),
 			},
 			StorageClassName: &pvcRequestConfig.StorageClass,
 			VolumeMode:       &volMode,
@@ -451,13 +1900,19 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		zap.String("namespace", srcPVCSpecification.Namespace))
 
 	// Create source PVC Spec
-	_, err = pvcClient.Create(ctx, &srcPVCSpecification, metaV1.CreateOptions{})
+	srcPVC, err := pvcClient.Create(ctx, &srcPVCSpecification, metaV1.CreateOptions{})
 	if err != nil {
 		return err
 	}
 
+	if !fastStart {
+		a.event(srcPVC, EventSizeComputed, "Sized %d objects at %d bytes from %s/%s",
+			objCount, sz, pvcRequestConfig.S3Bucket, pvcRequestConfig.S3Prefix)
+	}
+	a.event(srcPVC, EventSourceCreated, "Created source PVC requesting %s", storageQtyBuffer.String())
+
 	// rolling backoff check for proper PVC status
-	err = a.checkPVC(pvcRequestConfig.Namespace, srcPVCName)
+	err = a.checkPVC(pvcRequestConfig.Namespace, srcPVCName, pvcRequestConfig.StorageClass, "src")
 	if err != nil {
 		a.Log.Error("checkPVC failed",
 			zap.String("name", srcPVCName),
@@ -467,6 +1922,57 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		return err
 	}
 
+	// a bound fast start PVC may be resized to the bucket size
+	if fastStart {
+		res := <-sized
+		if res.err != nil {
+			if a.CleanupOnFailure {
+				_ = pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+			}
+			return res.err
+		}
+
+		objCount, sz = res.objCount, res.size
+		storageQtyBuffer = a.storageRequest(pvcRequestConfig, sz, sizeMultiplier)
+
+		srcPVC, err = a.resizePVC(srcPVC, storageQtyBuffer, objCount, sz)
+		if err != nil {
+			if a.CleanupOnFailure {
+				_ = pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+			}
+			return err
+		}
+		storageQtyBuffer = srcPVC.Spec.Resources.Requests[coreV1.ResourceStorage]
+
+		a.event(srcPVC, EventSizeComputed, "Sized %d objects at %d bytes from %s/%s",
+			objCount, sz, pvcRequestConfig.S3Bucket, pvcRequestConfig.S3Prefix)
+	}
+
+	// calculate run estimate
+	pvcRequestConfig.CopyStrategy = a.copyStrategy(pvcRequestConfig, objCount)
+	runEst := a.transferEstimate(pvcRequestConfig, pvcRequestConfig.CopyStrategy, objCount, sz)
+
+	// calculate timeouts at a slow 5mb/sec
+	a.Log.Info("CreatePVC called",
+		zap.Int64("object_count", objCount),
+		zap.Int64("size", sz),
+		zap.Int64("run_est", runEst),
+		zap.Int("run_est_cfg_mps", a.tunables().AvgMPS),
+		zap.String("copy_strategy", pvcRequestConfig.CopyStrategy),
+		zap.Bool("fast_start", fastStart),
+		zap.String("name", pvcRequestConfig.Name),
+		zap.String("namespace", pvcRequestConfig.Namespace),
+		zap.String("bucket", pvcRequestConfig.S3Bucket),
+		zap.String("prefix", pvcRequestConfig.S3Prefix),
+		zap.String("s3_endpoint", pvcRequestConfig.S3Endpoint),
+		zap.Any("vol_config", pvcRequestConfig.VolConfig),
+	)
+
+	inFlightBytes := bytesInFlight.WithLabelValues(pvcRequestConfig.Namespace)
+	inFlightBytes.Add(float64(sz))
+	a.operations.setSize(pvcRequestConfig.Namespace, pvcRequestConfig.Name, objCount, sz)
+	defer inFlightBytes.Sub(float64(sz))
+
 	// create a Job with MinIO client Pod attached to the new srcPVCSpecification
 	jobsClient := a.Cs.BatchV1().Jobs(pvcRequestConfig.Namespace)
 
@@ -475,32 +1981,37 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		objStoreEpProto = "https://"
 	}
 
-	objStoreEp := fmt.Sprintf(
-		"%s%s:%s@%s",
-		objStoreEpProto,
-		pvcRequestConfig.S3Key,
-		pvcRequestConfig.S3Secret,
+	// with a TLS server name override the injector connects by that
+	// name, as its tools have no SNI option. The name resolves through
+	// cluster DNS or the configured and requested host aliases.
+	objStoreHost := pvcRequestConfig.injectorEndpoint()
+
+	jobName := fmt.Sprintf("%s-injector", pvcRequestConfig.Name)
+
+	container := a.injectorContainer(pvcRequestConfig, objStoreEpProto, objStoreHost)
+
+	// mesh/sidecar control and other custom annotations, PVCI's own
+	// annotations take precedence
+	podAnnotations := make(map[string]string)
+	for _, custom := range []map[string]string{a.InjectorAnnotations, pvcRequestConfig.Annotations} {
+		for k, v := range custom {
+			podAnnotations[k] = v
+		}
+	}
+	podAnnotations["pvci.txn2.com/requested_size"] = strconv.FormatInt(sz, 10)
+	podAnnotations["pvci.txn2.com/object_count"] = strconv.FormatInt(objCount, 10)
+	podAnnotations["pvci.txn2.com/origin"] = fmt.Sprintf("%s/%s/%s",
 		pvcRequestConfig.S3Endpoint,
-	)
-
-	objPath := fmt.Sprintf(
-		"%s/%s",
 		pvcRequestConfig.S3Bucket,
 		pvcRequestConfig.S3Prefix,
 	)
-
-	jobName := fmt.Sprintf("%s-injector", pvcRequestConfig.Name)
+	stampTrace(podAnnotations, pvcRequestConfig)
 
 	jobSpecification := batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      jobName,
 			Namespace: pvcRequestConfig.Namespace,
-			Labels: map[string]string{
-				"pvci.txn2.com/vol":     pvcRequestConfig.Name,
-				"pvci.txn2.com/job":     "injector",
-				"pvci.txn2.com/service": a.Service,
-				"pvci.txn2.com/version": a.Version,
-			},
+			Labels:    a.injectorLabels(pvcRequestConfig.Name),
 			Annotations: map[string]string{
 				"pvci.txn2.com/requested_size": strconv.FormatInt(sz, 10),
 				"pvci.txn2.com/object_count":   strconv.FormatInt(objCount, 10),
@@ -514,21 +2025,8 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		Spec: batchV1.JobSpec{
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
-						"pvci.txn2.com/vol":     pvcRequestConfig.Name,
-						"pvci.txn2.com/job":     "injector",
-						"pvci.txn2.com/service": a.Service,
-						"pvci.txn2.com/version": a.Version,
-					},
-					Annotations: map[string]string{
-						"pvci.txn2.com/requested_size": strconv.FormatInt(sz, 10),
-						"pvci.txn2.com/object_count":   strconv.FormatInt(objCount, 10),
-						"pvci.txn2.com/origin": fmt.Sprintf("%s/%s/%s",
-							pvcRequestConfig.S3Endpoint,
-							pvcRequestConfig.S3Bucket,
-							pvcRequestConfig.S3Prefix,
-						),
-					},
+					Labels:      podLabels,
+					Annotations: podAnnotations,
 				},
 				Spec: coreV1.PodSpec{
 					RestartPolicy: coreV1.RestartPolicyOnFailure,
@@ -543,37 +2041,59 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 							},
 						},
 					},
-					Containers: []coreV1.Container{
-						{
-							Name:  "mc",
-							Image: a.MCImage,
-							Command: []string{
-								"mc",
-								"cp",
-								"-r",
-								"objstore/" + objPath,
-								"/srcpvc",
-							},
-							VolumeMounts: []coreV1.VolumeMount{
-								{
-									MountPath: "/srcpvc",
-									Name:      "srcpvc",
-								},
-							},
-							Env: []coreV1.EnvVar{
-								{
-									Name:  "MC_HOST_objstore",
-									Value: objStoreEp,
-								},
-							},
-						},
-					},
+					InitContainers: []coreV1.Container{container},
+					Containers:     []coreV1.Container{a.verifyContainer()},
 				},
 			},
 		},
 	}
 
+	stampTrace(jobSpecification.Annotations, pvcRequestConfig)
+
+	// the hard ceiling also bounds the Job should PVCI stop watching
+	deadline := a.jobDeadline(pvcRequestConfig.InjectorConfig)
+	if deadline.ceiling > 0 {
+		activeDeadline := int64(deadline.ceiling.Seconds())
+		jobSpecification.Spec.ActiveDeadlineSeconds = &activeDeadline
+	}
+
+	// route objects into partitions or delimited directories
+	// with a copy plan
+	if usesPlan(pvcRequestConfig) {
+		planName, err := a.createPartitionPlan(pvcRequestConfig)
+		if err != nil {
+			if a.CleanupOnFailure {
+				_ = pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+			}
+			return err
+		}
+		defer a.deletePartitionPlan(pvcRequestConfig.Namespace, planName)
+
+		a.partitionPod(&jobSpecification.Spec.Template.Spec, pvcRequestConfig, planName)
+		shardJob(&jobSpecification.Spec, pvcRequestConfig)
+	}
+
+	extraVolumesPod(&jobSpecification.Spec.Template.Spec, pvcRequestConfig)
+	a.injectorDNSPod(&jobSpecification.Spec.Template.Spec, pvcRequestConfig)
+
+	// mount credentials as an mc config rather than the environment
+	if a.usesMCConfig(pvcRequestConfig) {
+		secretName, err := a.createMCConfig(pvcRequestConfig, objStoreEpProto+objStoreHost)
+		if err != nil {
+			if a.CleanupOnFailure {
+				_ = pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+			}
+			return err
+		}
+		defer a.deleteMCConfig(pvcRequestConfig.Namespace, secretName)
+
+		mcConfigPod(&jobSpecification.Spec.Template.Spec, secretName)
+	}
+
 	_, err = jobsClient.Create(ctx, &jobSpecification, metaV1.CreateOptions{})
+	if apiErrors.IsAlreadyExists(err) && a.AdoptInjectorJobs {
+		err = a.adoptInjectorJob(&jobSpecification, pvcRequestConfig.Name)
+	}
 	if err != nil {
 		a.Log.Error("could not create job",
 			zap.String("namespace", pvcRequestConfig.Namespace),
@@ -582,24 +2102,72 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		)
 
 		// clean up on fail
-		cleanErr := pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
-		if err != nil {
-			a.Log.Error("could not delete pvc",
-				zap.String("namespace", pvcRequestConfig.Namespace),
-				zap.String("name", srcPVCName),
-				zap.Error(cleanErr),
-			)
+		if a.CleanupOnFailure {
+			cleanErr := pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+			if cleanErr != nil {
+				a.Log.Error("could not delete pvc",
+					zap.String("namespace", pvcRequestConfig.Namespace),
+					zap.String("name", srcPVCName),
+					zap.Error(cleanErr),
+				)
+			}
 		}
 
 		return err
 	}
 
+	a.event(srcPVC, EventInjectionStarted, "Started injector Job %s", jobName)
+
 	// check job status (up to 60 seconds)
-	err = a.checkJob(pvcRequestConfig.Namespace, jobName, runEst)
+	err = a.checkJob(pvcRequestConfig.Namespace, jobName, runEst, pvcRequestConfig.StorageClass, deadline)
 	if err != nil {
+		a.warning(srcPVC, EventInjectionFailed, "Injector Job %s failed: %s", jobName, err.Error())
+		if a.CleanupOnFailure {
+			a.cleanupInjector(pvcRequestConfig.Namespace, jobName, srcPVCName)
+		}
 		return err
 	}
 
+	a.event(srcPVC, EventInjectionSucceeded, "Injector Job %s completed", jobName)
+
+	// compare the files landed with the objects sized
+	landed, err := a.landedObjects(pvcRequestConfig.Namespace, pvcRequestConfig.Name)
+	if err != nil {
+		a.Log.Warn("unable to verify landed objects",
+			zap.String("name", srcPVCName),
+			zap.String("namespace", pvcRequestConfig.Namespace),
+			zap.Error(err),
+		)
+	}
+
+	// without a listing the landed files are the only object count
+	if pvcRequestConfig.SkipSizeCompute && landed >= 0 {
+		objCount = landed
+	}
+
+	verification := newVerification(objCount, landed)
+	switch {
+	case verification.Empty:
+		a.warning(srcPVC, EventEmptyVolume, "No files landed from %d objects", objCount)
+	case verification.Mismatch:
+		a.warning(srcPVC, EventObjectCountMismatch, "%d files landed from %d objects", landed, objCount)
+	}
+
+	// objects listed but not copied are data silently dropped
+	warnings := make([]string, 0)
+	if strategyWarning != "" {
+		warnings = append(warnings, strategyWarning)
+	}
+	if err := countMismatch(objCount, landed); err != nil {
+		if a.FailOnCountMismatch {
+			if a.CleanupOnFailure {
+				a.cleanupInjector(pvcRequestConfig.Namespace, jobName, srcPVCName)
+			}
+			return err
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: %s", ErrCodeCountMismatch, err.Error()))
+	}
+
 	// cleanup job
 	err = jobsClient.Delete(ctx, jobName, metaV1.DeleteOptions{})
 	if err != nil {
@@ -610,15 +2178,17 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		)
 	}
 
+	if direct {
+		return a.finishDirectInject(srcPVC, pvcRequestConfig, landed, warnings)
+	}
+
 	// Create roxPVC from srcPVC
+	finalStorageClass := pvcRequestConfig.finalStorageClass()
 	pvcSpecification := coreV1.PersistentVolumeClaim{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      pvcRequestConfig.Name,
 			Namespace: pvcRequestConfig.Namespace,
-			Labels: map[string]string{
-				"pvci.txn2.com/service": a.Service,
-				"pvci.txn2.com/version": a.Version,
-			},
+			Labels:    a.volLabels(pvcRequestConfig.Name),
 			Annotations: map[string]string{
 				"pvci.txn2.com/requested_size": strconv.FormatInt(sz, 10),
 				"pvci.txn2.com/object_count":   strconv.FormatInt(objCount, 10),
@@ -635,9 +2205,9 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 				Name: srcPVCName,
 			},
 			AccessModes: []coreV1.PersistentVolumeAccessMode{
-				"ReadOnlyMany",
+				pvcRequestConfig.finalAccessMode(),
 			},
-			StorageClassName: &pvcRequestConfig.StorageClass,
+			StorageClassName: &finalStorageClass,
 			VolumeMode:       &volMode,
 			Resources: coreV1.ResourceRequirements{
 				Requests: coreV1.ResourceList{
@@ -647,31 +2217,117 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		},
 	}
 
-	_, err = pvcClient.Create(ctx, &pvcSpecification, metaV1.CreateOptions{})
+	if landed >= 0 {
+		pvcSpecification.Annotations["pvci.txn2.com/landed_count"] = strconv.FormatInt(landed, 10)
+	}
+	if len(warnings) > 0 {
+		pvcSpecification.Annotations[warningsAnnotation] = strings.Join(warnings, "\n")
+	}
+	if pvcRequestConfig.AutoGrow {
+		pvcSpecification.Annotations[autoGrowAnnotation] = "true"
+	}
+	stampTrace(pvcSpecification.Annotations, pvcRequestConfig)
+	stampRequest(pvcSpecification.Annotations, pvcRequestConfig)
+
+	// a retried create may find the PVC of an attempt
+	// that failed after reaching the API server
+	var finalPVC *coreV1.PersistentVolumeClaim
+	attempted := false
+	err = a.retryClone("create", pvcRequestConfig.Name, isTransient, func() error {
+		finalPVC, err = pvcClient.Create(ctx, &pvcSpecification, metaV1.CreateOptions{})
+		if err != nil && attempted && apiErrors.IsAlreadyExists(err) {
+			finalPVC, err = pvcClient.Get(ctx, pvcRequestConfig.Name, metaV1.GetOptions{})
+		}
+		attempted = true
+		return err
+	})
+	if err != nil && cloneIncompatible(err.Error()) {
+		err = cloneIncompatibleError(pvcRequestConfig.Name, finalStorageClass, err.Error())
+	}
 	if err != nil {
-		// @TODO if error clean up src PVC
 		a.Log.Error("unable to create PVC",
 			zap.String("namespace", pvcRequestConfig.Namespace),
 			zap.String("name", pvcRequestConfig.Name),
 			zap.Error(err),
 		)
 
+		if a.CleanupOnFailure {
+			a.cleanupFinal(pvcRequestConfig.Namespace, pvcRequestConfig.Name, srcPVCName)
+		}
 		return err
 	}
 
 	// rolling backoff check for proper PVC status
-	err = a.checkPVC(pvcRequestConfig.Namespace, srcPVCName)
+	// a clone may fail to provision until its source is ready
+	err = a.retryClone("bind", pvcRequestConfig.Name, func(err error) bool {
+		code, _ := ErrorStatus(err)
+		return !apiErrors.IsNotFound(err) && code != ErrCodeCloneIncompatible
+	}, func() error {
+		return a.checkPVC(pvcRequestConfig.Namespace, pvcRequestConfig.Name, finalStorageClass, "final")
+	})
 	if err != nil {
-		// @TODO if error clean up src PVC
 		a.Log.Error("checkPVC failed",
-			zap.String("name", srcPVCName),
-			zap.String("namespace", srcPVCSpecification.Namespace),
+			zap.String("name", pvcRequestConfig.Name),
+			zap.String("namespace", pvcRequestConfig.Namespace),
 			zap.Error(err),
 		)
 
+		if a.CleanupOnFailure {
+			a.cleanupFinal(pvcRequestConfig.Namespace, pvcRequestConfig.Name, srcPVCName)
+		}
 		return err
 	}
 
+	a.event(finalPVC, EventCloned, "Cloned from source PVC %s", srcPVCName)
+
+	// confirm the final PVC mounts for consumers
+	if pvcRequestConfig.VerifyConsumable {
+		err = a.verifyConsumable(pvcRequestConfig)
+		if err != nil {
+			a.warning(finalPVC, EventConsumerVerifyFailed, "%s", err.Error())
+			if a.CleanupOnFailure {
+				a.cleanupFinal(pvcRequestConfig.Namespace, pvcRequestConfig.Name, srcPVCName)
+			}
+			return err
+		}
+	}
+
+	// a WaitForFirstConsumer clone is provisioned from its source
+	// only once mounted, after the create returns
+	awaitingClone := false
+	if pvc, err := a.getPVC(pvcRequestConfig.Namespace, pvcRequestConfig.Name); err == nil {
+		awaitingClone = pvc.Status.Phase != coreV1.ClaimBound
+	}
+
+	// a debug retained source PVC is left for inspection
+	if pvcRequestConfig.DebugKeepSource {
+		a.Log.Warn("retaining source PVC for debugging",
+			zap.String("name", srcPVCName),
+			zap.String("namespace", srcPVCSpecification.Namespace),
+		)
+
+		return nil
+	}
+
+	// the source PVC is left for Reconcile to delete once the clone binds
+	if awaitingClone {
+		a.Log.Info("keeping source PVC until the clone binds",
+			zap.String("name", srcPVCName),
+			zap.String("namespace", srcPVCSpecification.Namespace),
+		)
+
+		err = a.keepSourceForClone(pvcRequestConfig.Namespace, srcPVCName)
+		if err != nil {
+			a.Log.Error("unable to label source PVC awaiting clone",
+				zap.String("name", srcPVCName),
+				zap.String("namespace", srcPVCSpecification.Namespace),
+				zap.Error(err),
+			)
+		}
+
+		return nil
+	}
+
 	// delete srcPVC
 	err = pvcClient.Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
 	if err != nil {
@@ -682,37 +2338,97 @@ func (a *API) CreatePVC(pvcRequestConfig PVCRequestConfig) error {
 		)
 	}
 
-	// patch pvc to remove finalizers for deletion
-	po := &PatchOperations{
-		{
-			Op:   "remove",
-			Path: "/metadata/finalizers/0",
-		},
-	}
-
-	poJson, _ := json.Marshal(po)
-
-	_, err = pvcClient.Patch(ctx, srcPVCName, types.JSONPatchType, poJson, metaV1.PatchOptions{})
+	// the source PVC usually deletes cleanly; only a PVC still
+	// held by a finalizer (e.g. CSI pvc-protection) needs patching
+	err = a.releaseSourcePVC(pvcRequestConfig.Namespace, srcPVCName)
 	if err != nil {
+		// the create succeeded, flag the leaked source PVC on the
+		// final PVC and leave it for reconcile
 		a.Log.Error("unable to patch source PVC",
 			zap.String("name", srcPVCName),
 			zap.String("namespace", srcPVCSpecification.Namespace),
 			zap.Error(err),
 		)
+
+		warning := fmt.Sprintf("source PVC %s was not released: %s", srcPVCName, err.Error())
+		a.warning(finalPVC, EventSourceLeaked, "%s", warning)
+
+		err = a.addWarning(finalPVC, warning)
+		if err != nil {
+			a.Log.Error("unable to annotate PVC warning",
+				zap.String("name", pvcRequestConfig.Name),
+				zap.String("namespace", pvcRequestConfig.Namespace),
+				zap.Error(err),
+			)
+		}
 	}
 
 	return nil
 }
 
+// storageRequest returns the storage to request for sz bytes, converting
+// MB to MiB and adding the overage for copy buffers before applying the
+// size multiplier of a request.
+func (a *API) storageRequest(pvcRequestConfig PVCRequestConfig, sz int64, sizeMultiplier float64) resource.Quantity {
+	overagePercent, overageFrom := a.overagePercent(pvcRequestConfig.VolConfig)
+	pctOver := 1 + (float64(overagePercent) / 100)
+	rawSize := (float64(sz) * 1.048576) * pctOver
+
+	exactSize := int64(math.Ceil(rawSize * sizeMultiplier))
+
+	storageQty := resource.Quantity{}
+	storageQty.Set(roundUpSize(exactSize, a.SizeGranularity))
+
+	a.Log.Info("Sized PVC",
+		zap.String("name", pvcRequestConfig.Name),
+		zap.String("namespace", pvcRequestConfig.Namespace),
+		zap.String("storage_class", pvcRequestConfig.StorageClass),
+		zap.Int("overage_pct", overagePercent),
+		zap.String("overage_from", overageFrom),
+		zap.Int64("raw_size", int64(math.Ceil(rawSize))),
+		zap.Float64("size_multiplier", sizeMultiplier),
+		zap.Int64("exact_size", exactSize),
+		zap.Int64("adjusted_size", storageQty.Value()),
+	)
+
+	return storageQty
+}
+
+// roundUpSize rounds sz bytes up to a multiple of granularity, leaving
+// it unchanged for a granularity of zero or less.
+func roundUpSize(sz int64, granularity int64) int64 {
+	if granularity <= 0 || sz%granularity == 0 {
+		return sz
+	}
+
+	return (sz/granularity + 1) * granularity
+}
+
+// overagePercent returns the volume overage percent of a request and
+// where it came from: the request's OveragePercent, the overage of its
+// storage class, or the global VolumeOveragePercent.
+func (a *API) overagePercent(volConfig VolConfig) (int, string) {
+	if volConfig.OveragePercent != nil {
+		return *volConfig.OveragePercent, "request"
+	}
+
+	if pct, ok := a.StorageClassOverage[volConfig.StorageClass]; ok {
+		return pct, "storage_class"
+	}
+
+	return a.tunables().VolumeOveragePercent, "global"
+}
+
 const JobAttemptInterval = 5
 
-// checkJob loops over a period for checking job status
-func (a *API) checkJob(namespace string, name string, timeout int64) error {
-	attempt := 0
+// jobMaxAttempts returns the number of status checks checkJob allows
+// for a run estimate in seconds, adding 50 percent overhead with a
+// floor of 6 attempts.
+func jobMaxAttempts(runEst int64) int {
 	maxAttempts := 1
 
 	// add 50 percent to overhead
-	maxTime := float64(timeout) + (float64(timeout) * .5)
+	maxTime := float64(runEst) + (float64(runEst) * .5)
 	if maxTime > JobAttemptInterval {
 		maxAttempts = int(math.Ceil(maxTime / JobAttemptInterval))
 	}
@@ -721,15 +2437,157 @@ func (a *API) checkJob(namespace string, name string, timeout int64) error {
 		maxAttempts = 6
 	}
 
+	return maxAttempts
+}
+
+// jobTimeout returns the seconds checkJob waits before giving up on a
+// Job for a run estimate, an interval before each of the allowed
+// attempts plus the interval of the final, failing check.
+func jobTimeout(runEst int64) int64 {
+	return int64(jobMaxAttempts(runEst)+2) * JobAttemptInterval
+}
+
+// runEstimate returns the estimated seconds to transfer
+// sz bytes at the configured AvgMPS.
+func (a *API) runEstimate(sz int64) int64 {
+	return sz / (int64(a.tunables().AvgMPS) * 1048576)
+}
+
+// ErrJobTimeout is returned by checkJob when the injector Job does not
+// complete in the time allotted by the run estimate.
+var ErrJobTimeout = errors.New("job is unable to complete in allotted time")
+
+// cleanupInjector deletes an injector Job along with its pods and the
+// source PVC it populates, used to stop transfers PVCI has given up on.
+func (a *API) cleanupInjector(namespace string, jobName string, srcPVCName string) {
+	ctx := context.Background()
+	propagation := metaV1.DeletePropagationBackground
+
+	a.Log.Info("Cleaning up injector",
+		zap.String("namespace", namespace),
+		zap.String("job", jobName),
+		zap.String("pvc", srcPVCName),
+	)
+
+	err := a.Cs.BatchV1().Jobs(namespace).Delete(ctx, jobName, metaV1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil {
+		a.Log.Error("unable to delete job",
+			zap.String("namespace", namespace),
+			zap.String("name", jobName),
+			zap.Error(err),
+		)
+	}
+
+	err = a.Cs.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, srcPVCName, metaV1.DeleteOptions{})
+	if err != nil {
+		a.Log.Error("unable to delete source PVC",
+			zap.String("namespace", namespace),
+			zap.String("name", srcPVCName),
+			zap.Error(err),
+		)
+	}
+}
+
+// checkJob loops over a period for checking job status. A watch on the
+// Job is used to detect completion as soon as it happens, while the
+// attempt interval bounds the total time allotted. With a stall window,
+// a Job whose source volume keeps growing runs past the allotted time
+// up to the deadline's ceiling. The run time of completed jobs is
+// recorded by storage class.
+func (a *API) checkJob(namespace string, name string, timeout int64, storageClass string, deadline jobDeadline) error {
+	ctx := context.Background()
+	start := time.Now()
+	attempt := 0
+
+	maxAttempts := jobMaxAttempts(timeout)
+
+	// source volume usage, the time it last grew and whether
+	// the injector has written its first data
+	lastUsed := int64(-1)
+	lastProgress := time.Now()
+	firstByte := false
+
+	observe := func() {
+		jobRunDuration.WithLabelValues(storageClass).Observe(time.Since(start).Seconds())
+	}
+
+	// progress samples remain for GetStatus after the run
+	a.progress.reset(namespace, name)
+	defer a.progress.expire(namespace, name)
+
+	// a nil events channel blocks forever, leaving only the interval
+	// polling below if the watch can not be established
+	var events <-chan watch.Event
+	watcher, err := a.Cs.BatchV1().Jobs(namespace).Watch(ctx, metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		a.Log.Warn("unable to watch job, falling back to polling",
+			zap.String("name", name),
+			zap.String("namespace", namespace),
+			zap.Error(err),
+		)
+	} else {
+		defer watcher.Stop()
+		events = watcher.ResultChan()
+	}
+
 	for {
-		time.Sleep(time.Duration(JobAttemptInterval) * time.Second)
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
 
-		if attempt > maxAttempts {
-			a.Log.Error("job is unable to complete in allotted time",
+			job, ok := evt.Object.(*batchV1.Job)
+			if !ok {
+				continue
+			}
+
+			if job.Status.Failed > 0 {
+				observe()
+				return a.jobFailure(namespace, name)
+			}
+
+			if jobSucceeded(*job) {
+				observe()
+				return nil
+			}
+
+			continue
+		case <-time.After(time.Duration(JobAttemptInterval) * time.Second):
+		}
+
+		if deadline.ceiling > 0 && time.Since(start) > deadline.ceiling {
+			a.Log.Error("job exceeded its max duration",
 				zap.String("name", name),
 				zap.String("namespace", namespace),
+				zap.Duration("max_duration", deadline.ceiling),
 			)
-			return fmt.Errorf("job is unable to complete in allotted time")
+			return ErrJobTimeout
+		}
+
+		if attempt > maxAttempts {
+			// a transfer still making progress extends the allotted time
+			progressing := deadline.stall > 0 && firstByte && time.Since(lastProgress) <= deadline.stall
+			if !progressing {
+				a.Log.Error("job is unable to complete in allotted time",
+					zap.String("name", name),
+					zap.String("namespace", namespace),
+				)
+				return ErrJobTimeout
+			}
+
+			if attempt == maxAttempts+1 {
+				a.Log.Info("job exceeded allotted time while progressing, extending",
+					zap.String("name", name),
+					zap.String("namespace", namespace),
+					zap.Duration("max_duration", deadline.ceiling),
+				)
+			}
 		}
 
 		job, err := a.getJob(namespace, name)
@@ -749,13 +2607,46 @@ func (a *API) checkJob(namespace string, name string, timeout int64) error {
 		)
 
 		if job.Status.Failed > 0 {
-			return fmt.Errorf("job failed")
+			observe()
+			return a.jobFailure(namespace, name)
 		}
 
-		if job.Status.Succeeded > 0 {
+		if jobSucceeded(*job) {
+			observe()
 			return nil
 		}
 
+		used, err := a.volumeUsedBytes(namespace, name, "srcpvc")
+		if err == nil {
+			a.progress.record(namespace, name, used)
+		}
+
+		switch {
+		case err != nil:
+			a.Log.Debug("unable to get source volume usage",
+				zap.String("name", name),
+				zap.String("namespace", namespace),
+				zap.Error(err),
+			)
+		case used != lastUsed:
+			// the first growth past the initial usage of the
+			// volume is the first data written by the injector
+			if lastUsed >= 0 && !firstByte {
+				firstByte = true
+				injectorTTFB.WithLabelValues(storageClass).Observe(time.Since(start).Seconds())
+			}
+			lastUsed = used
+			lastProgress = time.Now()
+		case deadline.stall > 0 && time.Since(lastProgress) > deadline.stall:
+			a.Log.Error("job made no progress within the stall timeout",
+				zap.String("name", name),
+				zap.String("namespace", namespace),
+				zap.Int64("used_bytes", used),
+				zap.Duration("stall_timeout", deadline.stall),
+			)
+			return ErrJobStalled
+		}
+
 		attempt += 1
 	}
 }
@@ -772,16 +2663,30 @@ func (a *API) getJob(namespace string, name string) (*batchV1.Job, error) {
 	return job, nil
 }
 
-func (a *API) checkPVC(namespace string, name string) error {
+// checkPVC waits with a rolling backoff for a PVC to reach the Bound
+// phase and records the time taken by storage class and role (src or
+// final).
+func (a *API) checkPVC(namespace string, name string, storageClass string, role string) error {
+	start := time.Now()
 	attempt := 0
+	waitsForConsumer := a.waitsForFirstConsumer(storageClass)
 	retrySecs := []int{1, 2, 2, 4, 4, 4, 8, 8, 8, 8, 8}
+
+	// the last provisioning event and the polls it failed over
+	lastEvent := ""
+	failedPolls := 0
+
 	//var srcPVC *coreV1.PersistentVolumeClaim
 	for {
 		if attempt > len(retrySecs)-1 {
 			a.Log.Error("requested PVC is unable to reach Bound phase",
 				zap.String("name", name),
 				zap.String("namespace", namespace),
+				zap.String("last_event", lastEvent),
 			)
+			if lastEvent != "" {
+				return fmt.Errorf("requested PVC is unable to reach Bound phase, last event %s", lastEvent)
+			}
 			return fmt.Errorf("requested PVC is unable to reach Bound phase")
 		}
 
@@ -792,14 +2697,64 @@ func (a *API) checkPVC(namespace string, name string) error {
 			return err
 		}
 
+		if srcPVC.Status.Phase == coreV1.ClaimBound {
+			a.Log.Info("PVC status phase",
+				zap.String("name", name),
+				zap.String("namespace", namespace),
+				zap.Any("status", srcPVC.Status.Phase))
+			pvcBindDuration.WithLabelValues(storageClass, role).Observe(time.Since(start).Seconds())
+			return nil
+		}
+
+		// report provisioning progress, failing fast when
+		// the provisioner gives up
+		event, err := a.provisioningEvent(srcPVC)
+		if err != nil {
+			a.Log.Warn("unable to get PVC provisioning events",
+				zap.String("name", name),
+				zap.String("namespace", namespace),
+				zap.Error(err))
+		}
+
+		reason, message := "", ""
+		if event != nil {
+			reason, message = event.Reason, event.Message
+			lastEvent = reason + ": " + message
+		}
+
 		a.Log.Info("PVC status phase",
 			zap.String("name", name),
 			zap.String("namespace", namespace),
-			zap.Any("status", srcPVC.Status.Phase))
-		if srcPVC.Status.Phase == coreV1.ClaimBound {
+			zap.Any("status", srcPVC.Status.Phase),
+			zap.String("reason", reason),
+			zap.String("message", message))
+
+		// the PVC binds once mounted, by the injector for a source
+		// PVC or the first consumer of a final PVC
+		if waitsForConsumer && srcPVC.Status.Phase == coreV1.ClaimPending && reason == WaitForFirstConsumer {
+			a.Log.Info("PVC waits for its first consumer",
+				zap.String("name", name),
+				zap.String("namespace", namespace),
+				zap.String("storage_class", storageClass))
 			return nil
 		}
 
+		if reason == ProvisioningFailed && role == "final" && cloneIncompatible(message) {
+			return cloneIncompatibleError(name, storageClass, message)
+		}
+
+		// provisioners retry failures, only a failure persisting
+		// over consecutive polls fails the create
+		if reason == ProvisioningFailed {
+			failedPolls += 1
+		} else {
+			failedPolls = 0
+		}
+
+		if failedPolls >= ProvisioningFailedPolls {
+			return fmt.Errorf("PVC %s provisioning failed: %s", name, message)
+		}
+
 		attempt += 1
 	}
 }
@@ -820,30 +2775,87 @@ func (a *API) getPVC(namespace string, name string) (*coreV1.PersistentVolumeCla
 // MinIO or S3. See: https://docs.min.io/docs/golang-client-api-reference
 func (a *API) getMinIOClient(pvcRequestConfig PVCRequestConfig) (*minio.Client, error) {
 
+	newClient := minio.New
+	newCreds := credentials.NewStaticV4
+	switch pvcRequestConfig.S3SignatureVersion {
+	case "", SignatureV4:
+	case SignatureV2:
+		newClient = minio.NewV2
+		newCreds = credentials.NewStaticV2
+	default:
+		return nil, badRequest("unknown s3_signature_version %s", pvcRequestConfig.S3SignatureVersion)
+	}
+
 	// Initialize MinIO client object.
-	minioClient, err := minio.New(
-		pvcRequestConfig.S3Endpoint,
-		pvcRequestConfig.S3Key,
-		pvcRequestConfig.S3Secret,
-		pvcRequestConfig.S3SSL,
-	)
+	key, secret := pvcRequestConfig.listCredentials()
+
+	var minioClient *minio.Client
+	var err error
+	if pvcRequestConfig.S3Region != "" {
+		minioClient, err = minio.NewWithOptions(pvcRequestConfig.S3Endpoint, &minio.Options{
+			Creds:        newCreds(key, secret, ""),
+			Secure:       pvcRequestConfig.S3SSL,
+			Region:       pvcRequestConfig.S3Region,
+			BucketLookup: minio.BucketLookupAuto,
+		})
+	} else {
+		minioClient, err = newClient(
+			pvcRequestConfig.S3Endpoint,
+			key,
+			secret,
+			pvcRequestConfig.S3SSL,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := a.getS3Transport(pvcRequestConfig.S3Config)
 	if err != nil {
 		return nil, err
 	}
+	minioClient.SetCustomTransport(transport)
 
 	return minioClient, err
 }
 
+// getS3Transport returns the HTTP transport used for requests to the
+// object store described by an S3Config.
+func (a *API) getS3Transport(s3Config S3Config) (http.RoundTripper, error) {
+	transport, err := minio.DefaultTransport(s3Config.S3SSL)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, ok := transport.(*http.Transport)
+	if !ok {
+		return transport, nil
+	}
+
+	a.S3Transport.merge(s3Config.S3Transport).apply(tr)
+
+	if s3Config.S3SSL && s3Config.S3TLSServerName != "" {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tr.TLSClientConfig.ServerName = s3Config.S3TLSServerName
+	}
+
+	return tr, nil
+}
+
 // parsePVCRequestConfig is used to Unmarshal JSON representing the PVCRequestConfig
 // sent in on POST from most inbound API calls.
 func (a *API) parsePVCRequestConfig(c *gin.Context) (*PVCRequestConfig, error) {
-	rs, err := c.GetRawData()
+	pvcRequestConfig := &PVCRequestConfig{}
+	err := a.parseBody(c, pvcRequestConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	pvcRequestConfig := &PVCRequestConfig{}
-	err = json.Unmarshal(rs, pvcRequestConfig)
+	pvcRequestConfig.TraceParent = c.GetHeader(a.TraceHeader)
+
+	err = a.checkCallbackURL(pvcRequestConfig.CallbackURL)
 	if err != nil {
 		return nil, err
 	}
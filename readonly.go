@@ -0,0 +1,92 @@
+package pvci
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// readOnly tracks the runtime read-only mode, started from
+// Config.ReadOnly.
+type readOnly struct {
+	enabled int32
+}
+
+func (ro *readOnly) set(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&ro.enabled, v)
+}
+
+func (ro *readOnly) get() bool {
+	return atomic.LoadInt32(&ro.enabled) == 1
+}
+
+// ReadOnlyRequest structures the body of the /read-only endpoint.
+type ReadOnlyRequest struct {
+	ReadOnly bool `json:"read_only"`
+}
+
+// RequireWritable rejects requests with a 503 READ_ONLY error
+// while the API is in read-only mode.
+func (a *API) RequireWritable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.readOnly.get() {
+			a.abortWithError(c, newError(ErrCodeReadOnly, http.StatusServiceUnavailable,
+				"%s is read-only for maintenance", a.Service))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects requests without the Config.AdminToken bearer
+// token. Admin endpoints are forbidden when no token is configured.
+func (a *API) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.AdminToken == "" {
+			a.abortWithError(c, forbidden("admin endpoints are disabled"))
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.AdminToken)) != 1 {
+			a.abortWithError(c, newError(ErrCodeUnauthorized, http.StatusUnauthorized, "invalid admin token"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ReadOnlyHandler used by the HTTP POST /read-only endpoint to toggle
+// read-only mode at runtime, and GET /read-only to report it.
+func (a *API) ReadOnlyHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if c.Request.Method == http.MethodPost {
+			readOnlyRequest := &ReadOnlyRequest{}
+			err := a.parseBody(c, readOnlyRequest)
+			if err != nil {
+				a.abortWithParseError(c, err)
+				return
+			}
+
+			a.readOnly.set(readOnlyRequest.ReadOnly)
+
+			a.Log.Warn("read-only mode changed",
+				zap.Bool("read_only", readOnlyRequest.ReadOnly),
+				zap.String("remote_addr", c.ClientIP()),
+			)
+		}
+
+		c.JSON(http.StatusOK, ReadOnlyRequest{ReadOnly: a.readOnly.get()})
+	}
+}
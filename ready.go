@@ -0,0 +1,59 @@
+package pvci
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReadyTimeout bounds the dependency checks of a readiness probe.
+const ReadyTimeout = 5 * time.Second
+
+// Ready checks that PVCI can serve creates, returning an UNAVAILABLE
+// Error when the Kubernetes API can not list PVCs in the default
+// namespace, or a K8S_UNAVAILABLE Error while the K8sBreaker is open.
+func (a *API) Ready() error {
+	if a.K8sBreaker != nil {
+		if wait := a.K8sBreaker.retryAfter(); wait > 0 {
+			return k8sUnavailable(wait)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ReadyTimeout)
+	defer cancel()
+
+	_, err := a.Cs.CoreV1().PersistentVolumeClaims(a.DefaultNamespace).List(ctx, metaV1.ListOptions{Limit: 1})
+	if err != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			return err
+		}
+		return unavailable("kubernetes API is not ready: %s", err.Error())
+	}
+
+	return nil
+}
+
+// ReadyHandler used by the HTTP GET /readyz endpoint responds 200 when
+// PVCI is Ready and 503 otherwise, reporting the K8sBreaker state
+// when configured.
+func (a *API) ReadyHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := a.Ready()
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		resp := gin.H{"ready": true}
+		if a.K8sBreaker != nil {
+			resp["k8s_breaker"] = a.K8sBreaker.State()
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,326 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultReconcileTTL is the age past which finished injector Jobs and
+// orphaned source PVCs are reconciled when Config.ReconcileTTL is not set.
+const DefaultReconcileTTL = time.Hour
+
+// ReconcileRequest structures the optional body of the /reconcile
+// endpoint. An empty Namespace reconciles every allowed namespace, or
+// all namespaces without an allow-list.
+type ReconcileRequest struct {
+	Namespace string `json:"namespace"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+// ReconcileAction is a resource deleted, or to be deleted on a dry
+// run, by Reconcile.
+type ReconcileAction struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// ReconcileReport reports the actions taken by Reconcile.
+type ReconcileReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Actions []ReconcileAction `json:"actions"`
+	Errors  []string          `json:"errors"`
+}
+
+// ReconcileHandler used by the HTTP POST /reconcile endpoint to clean
+// up debris left by failed or interrupted creates.
+func (a *API) ReconcileHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		reconcileRequest := &ReconcileRequest{}
+		if c.Request.ContentLength != 0 {
+			err := a.parseBody(c, reconcileRequest)
+			if err != nil {
+				a.abortWithParseError(c, err)
+				return
+			}
+		}
+
+		report, err := a.Reconcile(*reconcileRequest)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ReconcileLoop runs Reconcile every interval, logging its actions.
+// Reconciles are skipped in read-only mode.
+func (a *API) ReconcileLoop(interval time.Duration) {
+	for range time.Tick(interval) {
+		if a.readOnly.get() {
+			continue
+		}
+
+		report, err := a.Reconcile(ReconcileRequest{})
+		if err != nil {
+			a.Log.Error("reconcile failed", zap.Error(err))
+			continue
+		}
+
+		a.Log.Info("reconciled",
+			zap.Int("actions", len(report.Actions)),
+			zap.Strings("errors", report.Errors),
+		)
+	}
+}
+
+// Reconcile deletes PVCI managed injector Jobs finished longer than
+// ReconcileTTL ago, along with source PVCs and copy plans left by
+// creates that are no longer running, and clears the finalizers of
+// source PVCs stuck terminating. Debug retained source PVCs are kept,
+// and source PVCs awaiting a WaitForFirstConsumer clone are deleted
+// once the clone binds.
+func (a *API) Reconcile(reconcileRequest ReconcileRequest) (ReconcileReport, error) {
+	report := ReconcileReport{
+		DryRun:  reconcileRequest.DryRun,
+		Actions: make([]ReconcileAction, 0),
+		Errors:  make([]string, 0),
+	}
+
+	namespaces := a.managedNamespaces()
+	if reconcileRequest.Namespace != "" {
+		pvcRequestConfig := PVCRequestConfig{VolConfig: VolConfig{Namespace: reconcileRequest.Namespace}}
+		err := a.resolveNamespace(&pvcRequestConfig)
+		if err != nil {
+			return report, err
+		}
+		namespaces = []string{pvcRequestConfig.Namespace}
+	}
+
+	for _, ns := range namespaces {
+		a.reconcileNamespace(ns, &report)
+	}
+
+	for _, action := range report.Actions {
+		a.Log.Info("reconcile action",
+			zap.Bool("dry_run", report.DryRun),
+			zap.String("kind", action.Kind),
+			zap.String("namespace", action.Namespace),
+			zap.String("name", action.Name),
+			zap.String("reason", action.Reason),
+		)
+	}
+
+	return report, nil
+}
+
+// managedNamespaces returns the namespaces PVCI may manage resources
+// in, the allowed namespaces or all namespaces without an allow-list.
+func (a *API) managedNamespaces() []string {
+	if len(a.AllowedNamespaces) > 0 {
+		return a.AllowedNamespaces
+	}
+
+	return []string{metaV1.NamespaceAll}
+}
+
+// reconcileNamespace reconciles the PVCI managed resources of a
+// namespace into a report.
+func (a *API) reconcileNamespace(namespace string, report *ReconcileReport) {
+	ctx := context.Background()
+	ttl := a.ReconcileTTL
+
+	selector := fmt.Sprintf("%s=%s", a.labelKey("service"), a.Service)
+	propagation := metaV1.DeletePropagationBackground
+
+	addErr := func(err error) {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	// injector Jobs still running, by namespace and volume
+	running := make(map[string]bool)
+
+	jobs, err := a.Cs.BatchV1().Jobs(namespace).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		addErr(err)
+		return
+	}
+
+	for _, job := range jobs.Items {
+		vol := job.Labels[a.labelKey("vol")]
+
+		finished, ok := jobFinished(job)
+		if !ok {
+			running[job.Namespace+"/"+vol] = true
+			continue
+		}
+
+		if time.Since(finished) < ttl {
+			running[job.Namespace+"/"+vol] = true
+			continue
+		}
+
+		report.Actions = append(report.Actions, ReconcileAction{
+			Kind: "Job", Namespace: job.Namespace, Name: job.Name,
+			Reason: fmt.Sprintf("finished %s ago", time.Since(finished).Round(time.Second)),
+		})
+
+		if !report.DryRun {
+			err = a.Cs.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metaV1.DeleteOptions{
+				PropagationPolicy: &propagation,
+			})
+			if err != nil {
+				addErr(err)
+			}
+		}
+	}
+
+	pvcs, err := a.Cs.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		addErr(err)
+		return
+	}
+
+	for _, pvc := range pvcs.Items {
+		vol := pvc.Labels[a.labelKey("vol")]
+		if pvc.Name != vol+"-src" {
+			continue
+		}
+
+		if pvc.Labels[a.labelKey("awaiting-clone")] == "true" && pvc.DeletionTimestamp == nil {
+			final, err := a.getPVC(pvc.Namespace, vol)
+			if err != nil && !apiErrors.IsNotFound(err) {
+				addErr(err)
+				continue
+			}
+
+			// the clone still needs its source
+			if err == nil && final.Status.Phase != coreV1.ClaimBound {
+				continue
+			}
+
+			report.Actions = append(report.Actions, ReconcileAction{
+				Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name,
+				Reason: "source PVC of a bound or deleted clone",
+			})
+
+			if !report.DryRun {
+				err = a.Cs.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metaV1.DeleteOptions{})
+				if err != nil {
+					addErr(err)
+				}
+			}
+
+			continue
+		}
+
+		if !orphaned(pvc.ObjectMeta, vol, ttl, running) {
+			continue
+		}
+
+		// a source PVC whose finalizer patch failed after a create
+		if pvc.DeletionTimestamp != nil {
+			if len(pvc.Finalizers) == 0 || time.Since(pvc.DeletionTimestamp.Time) < ttl {
+				continue
+			}
+
+			report.Actions = append(report.Actions, ReconcileAction{
+				Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name,
+				Reason: "source PVC stuck terminating",
+			})
+
+			if !report.DryRun {
+				err = a.removeFinalizers(pvc.Namespace, pvc.Name)
+				if err != nil {
+					addErr(err)
+				}
+			}
+
+			continue
+		}
+
+		if pvc.Labels[a.labelKey("debug-retained")] == "true" {
+			continue
+		}
+
+		report.Actions = append(report.Actions, ReconcileAction{
+			Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name,
+			Reason: "orphaned source PVC",
+		})
+
+		if !report.DryRun {
+			err = a.Cs.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metaV1.DeleteOptions{})
+			if err != nil {
+				addErr(err)
+			}
+		}
+	}
+
+	configMaps, err := a.Cs.CoreV1().ConfigMaps(namespace).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		addErr(err)
+		return
+	}
+
+	for _, cm := range configMaps.Items {
+		vol := cm.Labels[a.labelKey("vol")]
+		if cm.Name != vol+"-plan" || !orphaned(cm.ObjectMeta, vol, ttl, running) {
+			continue
+		}
+
+		report.Actions = append(report.Actions, ReconcileAction{
+			Kind: "ConfigMap", Namespace: cm.Namespace, Name: cm.Name,
+			Reason: "orphaned copy plan",
+		})
+
+		if !report.DryRun {
+			err = a.Cs.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metaV1.DeleteOptions{})
+			if err != nil {
+				addErr(err)
+			}
+		}
+	}
+}
+
+// orphaned reports whether a resource of a volume is older than ttl
+// without a running or recently finished injector.
+func orphaned(meta metaV1.ObjectMeta, vol string, ttl time.Duration, running map[string]bool) bool {
+	if time.Since(meta.CreationTimestamp.Time) < ttl {
+		return false
+	}
+
+	return !running[meta.Namespace+"/"+vol]
+}
+
+// jobFinished returns the time a Job completed or failed, and false
+// for Jobs still running.
+func jobFinished(job batchV1.Job) (time.Time, bool) {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime.Time, true
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchV1.JobComplete || cond.Type == batchV1.JobFailed) && cond.Status == coreV1.ConditionTrue {
+			return cond.LastTransitionTime.Time, true
+		}
+	}
+
+	if job.Status.Active == 0 && (job.Status.Succeeded > 0 || job.Status.Failed > 0) {
+		return job.CreationTimestamp.Time, true
+	}
+
+	return time.Time{}, false
+}
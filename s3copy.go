@@ -0,0 +1,104 @@
+package pvci
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v6"
+	"go.uber.org/zap"
+)
+
+// S3CopyRequest structures the body of the /s3-copy endpoint, copying
+// the objects under the S3Config's bucket and prefix to TargetBucket
+// and TargetPrefix on the same object store.
+type S3CopyRequest struct {
+	S3Config
+	TargetBucket string `json:"target_bucket"`
+	TargetPrefix string `json:"target_prefix"`
+}
+
+// S3CopyResult reports the objects copied by S3Copy.
+type S3CopyResult struct {
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// S3CopyHandler used by the HTTP POST /s3-copy endpoint to copy a
+// prefix between buckets of an object store.
+func (a *API) S3CopyHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		s3CopyRequest := &S3CopyRequest{}
+		err := a.parseBody(c, s3CopyRequest)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		result, err := a.S3Copy(*s3CopyRequest)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// S3Copy copies the objects of a prefix to a target bucket and prefix
+// with server-side copies, without transferring data through PVCI.
+// Object keys keep their path relative to the source prefix.
+func (a *API) S3Copy(s3CopyRequest S3CopyRequest) (S3CopyResult, error) {
+	result := S3CopyResult{}
+
+	if s3CopyRequest.TargetBucket == "" {
+		return result, badRequest("target_bucket is required")
+	}
+
+	if s3CopyRequest.TargetBucket == s3CopyRequest.S3Bucket &&
+		strings.HasPrefix(s3CopyRequest.TargetPrefix, s3CopyRequest.S3Prefix) {
+		return result, badRequest("target prefix %s overlaps source prefix %s",
+			s3CopyRequest.TargetPrefix, s3CopyRequest.S3Prefix)
+	}
+
+	pvcRequestConfig := PVCRequestConfig{S3Config: s3CopyRequest.S3Config}
+
+	minioClient, err := a.getMinIOClient(pvcRequestConfig)
+	if err != nil {
+		return result, err
+	}
+
+	a.Log.Info("S3Copy called",
+		zap.String("s3_endpoint", s3CopyRequest.S3Endpoint),
+		zap.String("bucket", s3CopyRequest.S3Bucket),
+		zap.String("prefix", s3CopyRequest.S3Prefix),
+		zap.String("target_bucket", s3CopyRequest.TargetBucket),
+		zap.String("target_prefix", s3CopyRequest.TargetPrefix),
+	)
+
+	err = a.listObjects(minioClient, pvcRequestConfig, func(object minio.ObjectInfo) error {
+		src := minio.NewSourceInfo(s3CopyRequest.S3Bucket, object.Key, nil)
+
+		dst, err := minio.NewDestinationInfo(
+			s3CopyRequest.TargetBucket,
+			s3CopyRequest.TargetPrefix+strings.TrimPrefix(object.Key, s3CopyRequest.S3Prefix),
+			nil, nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = minioClient.CopyObject(dst, src)
+		if err != nil {
+			return err
+		}
+
+		result.Objects += 1
+		result.Bytes += object.Size
+
+		return nil
+	})
+
+	return result, err
+}
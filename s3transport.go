@@ -0,0 +1,55 @@
+package pvci
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportTuning tunes the HTTP transport of the MinIO client used to
+// size buckets. Zero values keep the client defaults. IdleConnTimeout
+// and KeepAlive are in seconds.
+type TransportTuning struct {
+	MaxIdleConns    int `json:"max_idle_conns"`
+	MaxConnsPerHost int `json:"max_conns_per_host"`
+	IdleConnTimeout int `json:"idle_conn_timeout"`
+	KeepAlive       int `json:"keep_alive"`
+}
+
+// merge returns the tuning with the non-zero values of override applied.
+func (t TransportTuning) merge(override TransportTuning) TransportTuning {
+	if override.MaxIdleConns != 0 {
+		t.MaxIdleConns = override.MaxIdleConns
+	}
+	if override.MaxConnsPerHost != 0 {
+		t.MaxConnsPerHost = override.MaxConnsPerHost
+	}
+	if override.IdleConnTimeout != 0 {
+		t.IdleConnTimeout = override.IdleConnTimeout
+	}
+	if override.KeepAlive != 0 {
+		t.KeepAlive = override.KeepAlive
+	}
+
+	return t
+}
+
+// apply sets the non-zero values of the tuning on a transport.
+func (t TransportTuning) apply(tr *http.Transport) {
+	if t.MaxIdleConns > 0 {
+		tr.MaxIdleConns = t.MaxIdleConns
+		tr.MaxIdleConnsPerHost = t.MaxIdleConns
+	}
+	if t.MaxConnsPerHost > 0 {
+		tr.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = time.Duration(t.IdleConnTimeout) * time.Second
+	}
+	if t.KeepAlive > 0 {
+		tr.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: time.Duration(t.KeepAlive) * time.Second,
+		}).DialContext
+	}
+}
@@ -0,0 +1,69 @@
+package pvci
+
+import (
+	"strconv"
+
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// MaxShards is the largest number of injector pods a create may
+// shard its copy plan among.
+const MaxShards = 64
+
+// checkShards validates the Shards and Parallelism of a
+// PVCRequestConfig. The transport restrictions of copy plans are
+// checked by checkPartitionBy.
+func checkShards(pvcRequestConfig PVCRequestConfig) error {
+	shards, parallelism := pvcRequestConfig.Shards, pvcRequestConfig.Parallelism
+
+	switch {
+	case shards < 0 || shards > MaxShards:
+		return badRequest("shards must be between 0 and %d", MaxShards)
+	case parallelism < 0:
+		return badRequest("parallelism must not be negative")
+	case parallelism > 0 && shards <= 1:
+		return badRequest("parallelism requires shards")
+	case shards > 1 && pvcRequestConfig.srcAccessMode() != coreV1.ReadWriteMany:
+		// pods on different nodes mount the source PVC at once
+		return badRequest("shards require a src_access_mode of %s", coreV1.ReadWriteMany)
+	}
+
+	return nil
+}
+
+// shardJob runs the injector of a sharded PVCRequestConfig as an
+// Indexed Job of Shards completions, each pod copying the plan lines
+// of its completion index.
+func shardJob(jobSpec *batchV1.JobSpec, pvcRequestConfig PVCRequestConfig) {
+	if pvcRequestConfig.Shards <= 1 {
+		return
+	}
+
+	completions := int32(pvcRequestConfig.Shards)
+	parallelism := completions
+	if pvcRequestConfig.Parallelism > 0 && int32(pvcRequestConfig.Parallelism) < completions {
+		parallelism = int32(pvcRequestConfig.Parallelism)
+	}
+	mode := batchV1.IndexedCompletion
+
+	jobSpec.CompletionMode = &mode
+	jobSpec.Completions = &completions
+	jobSpec.Parallelism = &parallelism
+
+	container := &jobSpec.Template.Spec.InitContainers[0]
+	container.Env = append(container.Env, coreV1.EnvVar{
+		Name:  "PVCI_SHARDS",
+		Value: strconv.Itoa(pvcRequestConfig.Shards),
+	})
+}
+
+// jobSucceeded reports whether all completions of a Job succeeded.
+func jobSucceeded(job batchV1.Job) bool {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+
+	return job.Status.Succeeded >= completions
+}
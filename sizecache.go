@@ -0,0 +1,131 @@
+package pvci
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sizeCacheEntry is a cached GetSize result.
+type sizeCacheEntry struct {
+	objects int64
+	bytes   int64
+	expires time.Time
+}
+
+// sizeCache is a concurrency safe TTL cache of GetSize results.
+type sizeCache struct {
+	mu      sync.Mutex
+	entries map[string]sizeCacheEntry
+}
+
+func newSizeCache() *sizeCache {
+	return &sizeCache{entries: make(map[string]sizeCacheEntry)}
+}
+
+// sizeCacheKey identifies the objects selected by an S3Config.
+func sizeCacheKey(s3Config S3Config) string {
+	key, _ := s3Config.listCredentials()
+
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%t|%s|%s",
+		s3Config.S3Endpoint,
+		key,
+		s3Config.S3Bucket,
+		s3Config.S3Prefix,
+		s3Config.S3AsOf,
+		s3Config.SizeSource,
+		s3Config.S3InventoryKey,
+		s3Config.AllowKeyCollisions,
+		s3Config.delimiter(),
+		strings.Join(s3Config.ContentTypes, ","),
+	)
+}
+
+func (sc *sizeCache) get(key string) (int64, int64, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	entry, ok := sc.entries[key]
+	if !ok {
+		return 0, 0, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(sc.entries, key)
+		return 0, 0, false
+	}
+
+	return entry.objects, entry.bytes, true
+}
+
+func (sc *sizeCache) set(key string, objects int64, bytes int64, ttl time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.entries[key] = sizeCacheEntry{
+		objects: objects,
+		bytes:   bytes,
+		expires: time.Now().Add(ttl),
+	}
+}
+
+// PrewarmRequest structures the body of the /prewarm endpoint.
+type PrewarmRequest struct {
+	Objects []S3Config `json:"objects"`
+}
+
+// PrewarmResult reports the size cached for an S3Config by Prewarm.
+type PrewarmResult struct {
+	S3Bucket string `json:"s3_bucket"`
+	S3Prefix string `json:"s3_prefix"`
+	Objects  int64  `json:"objects"`
+	Bytes    int64  `json:"bytes"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PrewarmHandler used by the HTTP POST /prewarm endpoint to
+// populate the size cache for a list of buckets and prefixes.
+func (a *API) PrewarmHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		prewarmRequest := &PrewarmRequest{}
+		err := a.parseBody(c, prewarmRequest)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		if a.SizeCacheTTL <= 0 {
+			a.abortWithError(c, badRequest("size cache is disabled"))
+			return
+		}
+
+		c.JSON(http.StatusOK, a.Prewarm(*prewarmRequest))
+	}
+}
+
+// Prewarm refreshes the cached size of each S3Config in a PrewarmRequest.
+func (a *API) Prewarm(prewarmRequest PrewarmRequest) []PrewarmResult {
+	results := make([]PrewarmResult, 0)
+
+	for _, s3Config := range prewarmRequest.Objects {
+		s3Config.RefreshSize = true
+
+		result := PrewarmResult{S3Bucket: s3Config.S3Bucket, S3Prefix: s3Config.S3Prefix}
+
+		objCount, sz, err := a.GetSize(PVCRequestConfig{S3Config: s3Config})
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		result.Objects = objCount
+		result.Bytes = sz
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,120 @@
+package pvci
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Keys of a credentials Secret referenced by a GET /size request. The
+// credentials are only sent to the endpoint stored with them.
+const (
+	SecretKeyS3Endpoint = "s3_endpoint"
+	SecretKeyS3Key      = "s3_key"
+	SecretKeyS3Secret   = "s3_secret"
+)
+
+// GetSizeQueryHandler used by the HTTP GET endpoint /size to get the
+// size of a bucket and prefix given as query parameters named as the
+// /size POST body. Credentials may be read from the s3_key and s3_secret
+// keys of the Secret named by credentials_secret in the namespace
+// parameter, keeping them out of URLs and access logs. Only Secrets
+// labelled with the PVCI service are read, and their credentials only
+// sign requests to the s3_endpoint stored in the Secret.
+func (a *API) GetSizeQueryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		pvcRequestConfig, err := a.parseSizeQuery(c)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		cnt, sz, err := a.GetSize(*pvcRequestConfig)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"objects": cnt, "bytes": sz})
+	}
+}
+
+// parseSizeQuery reads a PVCRequestConfig from the query parameters
+// of a GET /size request.
+func (a *API) parseSizeQuery(c *gin.Context) (*PVCRequestConfig, error) {
+	pvcRequestConfig := &PVCRequestConfig{}
+	pvcRequestConfig.S3Endpoint = c.Query("s3_endpoint")
+	pvcRequestConfig.S3Bucket = c.Query("s3_bucket")
+	pvcRequestConfig.S3Prefix = c.Query("s3_prefix")
+	pvcRequestConfig.S3Key = c.Query("s3_key")
+	pvcRequestConfig.S3Secret = c.Query("s3_secret")
+	pvcRequestConfig.S3AsOf = c.Query("s3_as_of")
+	pvcRequestConfig.Namespace = c.Query("namespace")
+
+	for param, value := range map[string]*bool{
+		"s3_ssl":       &pvcRequestConfig.S3SSL,
+		"refresh_size": &pvcRequestConfig.RefreshSize,
+	} {
+		if c.Query(param) == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(c.Query(param))
+		if err != nil {
+			return nil, badRequest("%s must be a boolean", param)
+		}
+		*value = b
+	}
+
+	secretName := c.Query("credentials_secret")
+	if secretName == "" {
+		if pvcRequestConfig.S3Endpoint == "" || pvcRequestConfig.S3Bucket == "" {
+			return nil, badRequest("s3_endpoint and s3_bucket are required")
+		}
+
+		return pvcRequestConfig, nil
+	}
+
+	if pvcRequestConfig.S3Bucket == "" {
+		return nil, badRequest("s3_bucket is required")
+	}
+
+	err := a.resolveNamespace(pvcRequestConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// read on every request, with no cached clients, so rotated
+	// credentials apply without a restart
+	secret, err := a.Cs.CoreV1().Secrets(pvcRequestConfig.Namespace).Get(context.Background(), secretName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// any Secret of the namespace could otherwise be read by name
+	if secret.Labels[a.labelKey("service")] != a.Service {
+		return nil, forbidden("secret %s is not labelled %s=%s", secretName, a.labelKey("service"), a.Service)
+	}
+
+	for _, key := range []string{SecretKeyS3Endpoint, SecretKeyS3Key, SecretKeyS3Secret} {
+		if len(secret.Data[key]) == 0 {
+			return nil, badRequest("secret %s has no %s", secretName, key)
+		}
+	}
+
+	endpoint := string(secret.Data[SecretKeyS3Endpoint])
+	if pvcRequestConfig.S3Endpoint != "" && !strings.EqualFold(pvcRequestConfig.S3Endpoint, endpoint) {
+		return nil, forbidden("secret %s holds credentials for another s3_endpoint", secretName)
+	}
+
+	pvcRequestConfig.S3Endpoint = endpoint
+	pvcRequestConfig.S3Key = string(secret.Data[SecretKeyS3Key])
+	pvcRequestConfig.S3Secret = string(secret.Data[SecretKeyS3Secret])
+
+	return pvcRequestConfig, nil
+}
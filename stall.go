@@ -0,0 +1,116 @@
+package pvci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrJobStalled is returned by checkJob when the volume written by an
+// injector Job does not grow within the configured StallTimeout.
+var ErrJobStalled = errors.New("job made no progress within the stall timeout")
+
+// jobDeadline bounds an injector Job by a stall window without source
+// volume growth and a hard ceiling on its run time, zero disabling
+// either.
+type jobDeadline struct {
+	stall   time.Duration
+	ceiling time.Duration
+}
+
+// jobDeadline returns the deadline of an injector, the configured
+// StallTimeout and MaxInjectorDuration overridden per request.
+func (a *API) jobDeadline(injectorConfig InjectorConfig) jobDeadline {
+	a.tunablesMu.RLock()
+	deadline := jobDeadline{stall: a.StallTimeout, ceiling: a.MaxInjectorDuration}
+	a.tunablesMu.RUnlock()
+
+	if injectorConfig.StallTimeout > 0 {
+		deadline.stall = time.Duration(injectorConfig.StallTimeout) * time.Second
+	}
+
+	if injectorConfig.MaxDuration > 0 {
+		deadline.ceiling = time.Duration(injectorConfig.MaxDuration) * time.Second
+	}
+
+	return deadline
+}
+
+// statsSummary is the subset of the kubelet stats summary
+// reporting pod volume usage.
+type statsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Volume []struct {
+			Name          string `json:"name"`
+			UsedBytes     int64  `json:"usedBytes"`
+			CapacityBytes int64  `json:"capacityBytes"`
+			PVCRef        *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// nodeStatsSummary gets the kubelet stats summary of a node through
+// the API server's node proxy.
+func (a *API) nodeStatsSummary(ctx context.Context, node string) (statsSummary, error) {
+	summary := statsSummary{}
+
+	body, err := a.Cs.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", node, "proxy", "stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return summary, err
+	}
+
+	err = json.Unmarshal(body, &summary)
+
+	return summary, err
+}
+
+// volumeUsedBytes returns the bytes used on a volume of a Job's running
+// pod, as reported by the kubelet stats summary of the pod's node.
+func (a *API) volumeUsedBytes(namespace string, jobName string, volume string) (int64, error) {
+	ctx := context.Background()
+
+	pods, err := a.Cs.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: "job-name=" + jobName,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		summary, err := a.nodeStatsSummary(ctx, pod.Spec.NodeName)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, ps := range summary.Pods {
+			if ps.PodRef.Name != pod.Name || ps.PodRef.Namespace != namespace {
+				continue
+			}
+
+			for _, v := range ps.Volume {
+				if v.Name == volume {
+					return v.UsedBytes, nil
+				}
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no volume stats for %s of job %s", volume, jobName)
+}
@@ -0,0 +1,87 @@
+package pvci
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	storageV1 "k8s.io/api/storage/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// storageClassCache is a concurrency safe cache of the StorageClasses
+// read by the capability checks of creates.
+type storageClassCache struct {
+	mu      sync.Mutex
+	entries map[string]storageClassCacheEntry
+}
+
+type storageClassCacheEntry struct {
+	storageClass *storageV1.StorageClass
+	expires      time.Time
+}
+
+func newStorageClassCache() *storageClassCache {
+	return &storageClassCache{entries: make(map[string]storageClassCacheEntry)}
+}
+
+func (scc *storageClassCache) get(name string) (*storageV1.StorageClass, bool) {
+	scc.mu.Lock()
+	defer scc.mu.Unlock()
+
+	entry, ok := scc.entries[name]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(scc.entries, name)
+		return nil, false
+	}
+
+	return entry.storageClass, true
+}
+
+func (scc *storageClassCache) set(storageClass *storageV1.StorageClass, ttl time.Duration) {
+	scc.mu.Lock()
+	defer scc.mu.Unlock()
+
+	scc.entries[storageClass.Name] = storageClassCacheEntry{
+		storageClass: storageClass,
+		expires:      time.Now().Add(ttl),
+	}
+}
+
+// getStorageClass gets a StorageClass, cached for StorageClassCacheTTL
+// when set. Errors, including a missing storage class, are not cached.
+// The StorageClass returned is shared and must not be modified.
+func (a *API) getStorageClass(name string) (*storageV1.StorageClass, error) {
+	if a.StorageClassCacheTTL > 0 {
+		if sc, ok := a.scCache.get(name); ok {
+			return sc, nil
+		}
+	}
+
+	sc, err := a.Cs.StorageV1().StorageClasses().Get(context.Background(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.StorageClassCacheTTL > 0 {
+		a.scCache.set(sc, a.StorageClassCacheTTL)
+	}
+
+	return sc, nil
+}
+
+// waitsForFirstConsumer reports whether a storage class binds its
+// PVCs only once a pod mounts them. Unreadable classes are assumed to
+// bind immediately.
+func (a *API) waitsForFirstConsumer(storageClass string) bool {
+	sc, err := a.getStorageClass(storageClass)
+	if err != nil {
+		return false
+	}
+
+	return sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storageV1.VolumeBindingWaitForFirstConsumer
+}
@@ -0,0 +1,48 @@
+package pvci
+
+// Copy strategies of mc injectors.
+const (
+	CopyStrategyCopy   = "copy"
+	CopyStrategyMirror = "mirror"
+)
+
+// DefaultManyObjectsOPS is the default objects per second estimated for
+// the mirror copy strategy.
+const DefaultManyObjectsOPS = 100
+
+// copyStrategy returns the copy strategy of an injector transferring
+// objCount objects. mc injectors mirror rather than recursively copy
+// buckets of at least ManyObjectsThreshold objects, avoiding the per
+// object overhead of mc cp on many small files. Copy plans, as-of
+// copies and custom commands keep their own commands.
+func (a *API) copyStrategy(pvcRequestConfig PVCRequestConfig, objCount int64) string {
+	if a.ManyObjectsThreshold <= 0 || objCount < a.ManyObjectsThreshold {
+		return CopyStrategyCopy
+	}
+
+	if pvcRequestConfig.Transport != "" && pvcRequestConfig.Transport != TransportMC {
+		return CopyStrategyCopy
+	}
+
+	if usesPlan(pvcRequestConfig) || pvcRequestConfig.S3AsOf != "" || len(pvcRequestConfig.Command) > 0 {
+		return CopyStrategyCopy
+	}
+
+	return CopyStrategyMirror
+}
+
+// transferEstimate returns the estimated seconds the injector of a
+// PVCRequestConfig takes to transfer objCount objects of sz bytes with
+// a copy strategy. Mirrors are bounded by their object rate,
+// ManyObjectsOPS, as well as by bytes, and every transfer retry may
+// take as long again.
+func (a *API) transferEstimate(pvcRequestConfig PVCRequestConfig, strategy string, objCount int64, sz int64) int64 {
+	runEst := a.runEstimate(sz)
+	if strategy == CopyStrategyMirror {
+		if objEst := objCount / int64(a.ManyObjectsOPS); objEst > runEst {
+			runEst = objEst
+		}
+	}
+
+	return runEst * int64(a.transferRetries(pvcRequestConfig)+1)
+}
@@ -0,0 +1,77 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultJobSweepAge is the age past which finished injector Jobs are
+// swept when Config.JobSweepAge is not set.
+const DefaultJobSweepAge = time.Hour
+
+// SweepJobs deletes PVCI managed injector Jobs, with their pods,
+// finished longer than JobSweepAge ago, returning the Jobs deleted.
+// Unlike Reconcile it only touches Jobs, keeping namespaces clean on
+// clusters without Job TTLs.
+func (a *API) SweepJobs() ([]ReconcileAction, error) {
+	ctx := context.Background()
+
+	selector := fmt.Sprintf("%s=%s,%s=injector", a.labelKey("service"), a.Service, a.labelKey("job"))
+	propagation := metaV1.DeletePropagationBackground
+
+	swept := make([]ReconcileAction, 0)
+	for _, ns := range a.managedNamespaces() {
+		jobs, err := a.Cs.BatchV1().Jobs(ns).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return swept, err
+		}
+
+		for _, job := range jobs.Items {
+			finished, ok := jobFinished(job)
+			if !ok || time.Since(finished) < a.JobSweepAge {
+				continue
+			}
+
+			err = a.Cs.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metaV1.DeleteOptions{
+				PropagationPolicy: &propagation,
+			})
+			if err != nil {
+				return swept, err
+			}
+
+			swept = append(swept, ReconcileAction{
+				Kind: "Job", Namespace: job.Namespace, Name: job.Name,
+				Reason: fmt.Sprintf("finished %s ago", time.Since(finished).Round(time.Second)),
+			})
+		}
+	}
+
+	return swept, nil
+}
+
+// SweepLoop runs SweepJobs every interval, logging the Jobs deleted.
+// Sweeps are skipped in read-only mode.
+func (a *API) SweepLoop(interval time.Duration) {
+	for range time.Tick(interval) {
+		if a.readOnly.get() {
+			continue
+		}
+
+		swept, err := a.SweepJobs()
+		if err != nil {
+			a.Log.Error("job sweep failed", zap.Error(err))
+		}
+
+		for _, action := range swept {
+			a.Log.Info("swept job",
+				zap.String("namespace", action.Namespace),
+				zap.String("name", action.Name),
+				zap.String("reason", action.Reason),
+			)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package pvci
+
+import (
+	"go.uber.org/zap"
+)
+
+// DefaultTraceHeader carries the W3C trace context of a request.
+const DefaultTraceHeader = "traceparent"
+
+// traceAnnotation holds the trace context of the request
+// that created a resource.
+const traceAnnotation = "pvci.txn2.com/traceparent"
+
+// withTrace returns a copy of the API logging with the trace context
+// of a request, or the API itself for requests without one.
+func (a *API) withTrace(pvcRequestConfig PVCRequestConfig) *API {
+	if pvcRequestConfig.TraceParent == "" {
+		return a
+	}
+
+	a.tunablesMu.RLock()
+	cfg := *a.Config
+	a.tunablesMu.RUnlock()
+	cfg.Log = a.Log.With(zap.String("traceparent", pvcRequestConfig.TraceParent))
+
+	ta := *a
+	ta.Config = &cfg
+
+	return &ta
+}
+
+// stampTrace adds the trace context of a request to annotations.
+func stampTrace(annotations map[string]string, pvcRequestConfig PVCRequestConfig) {
+	if pvcRequestConfig.TraceParent != "" {
+		annotations[traceAnnotation] = pvcRequestConfig.TraceParent
+	}
+}
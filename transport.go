@@ -0,0 +1,248 @@
+package pvci
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// Transports are the copy tools an injector may run.
+const (
+	TransportMC     = "mc"
+	TransportRclone = "rclone"
+	TransportAWSCLI = "awscli"
+)
+
+// Default images of the non-mc transports.
+const (
+	DefaultRcloneImage = "rclone/rclone"
+	DefaultAWSCLIImage = "amazon/aws-cli"
+)
+
+// Request signature versions.
+const (
+	SignatureV4 = "v4"
+	SignatureV2 = "v2"
+)
+
+// checkTransport validates the transport of a PVCRequestConfig and
+// the options it supports.
+func checkTransport(pvcRequestConfig PVCRequestConfig) error {
+	switch pvcRequestConfig.S3SignatureVersion {
+	case "", SignatureV4, SignatureV2:
+	default:
+		return badRequest("unknown s3_signature_version %s", pvcRequestConfig.S3SignatureVersion)
+	}
+
+	if pvcRequestConfig.TransferRetries < 0 || pvcRequestConfig.TransferTimeout < 0 {
+		return badRequest("transfer_retries and transfer_timeout must not be negative")
+	}
+
+	switch pvcRequestConfig.Transport {
+	case "", TransportMC:
+		if pvcRequestConfig.TransferTimeout > 0 {
+			return badRequest("transfer_timeout is not supported by the %s transport", TransportMC)
+		}
+		return nil
+	case TransportRclone:
+		return nil
+	case TransportAWSCLI:
+		if pvcRequestConfig.S3AsOf != "" {
+			return badRequest("s3_as_of is not supported by the %s transport", TransportAWSCLI)
+		}
+		if pvcRequestConfig.PreserveMetadata {
+			return badRequest("preserve_metadata is not supported by the %s transport", TransportAWSCLI)
+		}
+		if pvcRequestConfig.S3SignatureVersion == SignatureV2 {
+			return badRequest("s3_signature_version %s is not supported by the %s transport", SignatureV2, TransportAWSCLI)
+		}
+		return nil
+	}
+
+	return badRequest("unknown transport %s", pvcRequestConfig.Transport)
+}
+
+// injectorContainer returns the container copying the objects of a
+// PVCRequestConfig from the object store at proto (http:// or https://)
+// and host into the source PVC mounted at /srcpvc. Objects land under
+// the last element of the prefix, as with mc.
+func (a *API) injectorContainer(pvcRequestConfig PVCRequestConfig, proto string, host string) coreV1.Container {
+	objStoreURL := proto + host
+	objPath := pvcRequestConfig.S3Bucket + "/" + pvcRequestConfig.S3Prefix
+	key, secret := pvcRequestConfig.transferCredentials()
+
+	target := "/srcpvc"
+	if prefix := strings.Trim(pvcRequestConfig.S3Prefix, "/"); prefix != "" {
+		target = path.Join(target, path.Base(prefix))
+	}
+
+	container := coreV1.Container{
+		VolumeMounts: []coreV1.VolumeMount{
+			{
+				MountPath: "/srcpvc",
+				Name:      "srcpvc",
+			},
+		},
+	}
+
+	switch pvcRequestConfig.Transport {
+	case TransportRclone:
+		container.Name = TransportRclone
+		container.Image = a.RcloneImage
+		container.Command = []string{"rclone", "copy"}
+		if pvcRequestConfig.PreserveMetadata {
+			container.Command = append(container.Command, "--metadata")
+		}
+		if pvcRequestConfig.S3AsOf != "" {
+			container.Command = append(container.Command, "--s3-version-at", pvcRequestConfig.S3AsOf)
+		}
+		container.Command = append(container.Command, "objstore:"+objPath, target)
+		container.Env = []coreV1.EnvVar{
+			{Name: "RCLONE_CONFIG_OBJSTORE_TYPE", Value: "s3"},
+			{Name: "RCLONE_CONFIG_OBJSTORE_PROVIDER", Value: "Other"},
+			{Name: "RCLONE_CONFIG_OBJSTORE_ENDPOINT", Value: objStoreURL},
+			{Name: "RCLONE_CONFIG_OBJSTORE_ACCESS_KEY_ID", Value: key},
+			{Name: "RCLONE_CONFIG_OBJSTORE_SECRET_ACCESS_KEY", Value: secret},
+		}
+		if pvcRequestConfig.S3SignatureVersion == SignatureV2 {
+			container.Env = append(container.Env, coreV1.EnvVar{Name: "RCLONE_CONFIG_OBJSTORE_V2_AUTH", Value: "true"})
+		}
+		if pvcRequestConfig.S3Region != "" {
+			container.Env = append(container.Env, coreV1.EnvVar{Name: "RCLONE_CONFIG_OBJSTORE_REGION", Value: pvcRequestConfig.S3Region})
+		}
+	case TransportAWSCLI:
+		container.Name = TransportAWSCLI
+		container.Image = a.AWSCLIImage
+		container.Command = []string{
+			"aws", "s3", "cp", "--recursive",
+			"--endpoint-url", objStoreURL,
+			"s3://" + objPath, target,
+		}
+		if pvcRequestConfig.S3Region != "" {
+			container.Command = append(container.Command, "--region", pvcRequestConfig.S3Region)
+		}
+		if key == "" {
+			container.Command = append(container.Command, "--no-sign-request")
+			break
+		}
+		container.Env = []coreV1.EnvVar{
+			{Name: "AWS_ACCESS_KEY_ID", Value: key},
+			{Name: "AWS_SECRET_ACCESS_KEY", Value: secret},
+		}
+	default:
+		container.Name = TransportMC
+		container.Image = a.MCImage
+		container.Command = []string{"mc", "cp", "-r"}
+		mcTarget := "/srcpvc"
+		if pvcRequestConfig.CopyStrategy == CopyStrategyMirror {
+			// mirror copies the prefix contents, not the prefix
+			container.Command = []string{"mc", "mirror", "--overwrite"}
+			mcTarget = target
+		}
+		if pvcRequestConfig.PreserveMetadata {
+			container.Command = append(container.Command, "--preserve")
+		}
+		if pvcRequestConfig.S3AsOf != "" {
+			container.Command = append(container.Command, "--rewind", pvcRequestConfig.S3AsOf)
+		}
+
+		// mc aliases have no region, mc reads it from the environment
+		if pvcRequestConfig.S3Region != "" {
+			container.Env = append(container.Env, coreV1.EnvVar{Name: "MC_REGION", Value: pvcRequestConfig.S3Region})
+		}
+
+		// credentials from a mounted mc config keep them out
+		// of the pod spec
+		if a.usesMCConfig(pvcRequestConfig) {
+			container.Command = append(container.Command, "--config-dir", mcConfigDir, "objstore/"+objPath, mcTarget)
+			break
+		}
+		container.Command = append(container.Command, "objstore/"+objPath, mcTarget)
+
+		mcHost := proto + host
+		if key != "" {
+			mcHost = fmt.Sprintf("%s%s:%s@%s", proto, key, secret, host)
+		}
+		container.Env = append(container.Env, coreV1.EnvVar{Name: "MC_HOST_objstore", Value: mcHost})
+	}
+
+	a.transferRetryContainer(&container, pvcRequestConfig)
+
+	// the custom command runs with the transport's image and env
+	if len(pvcRequestConfig.Command) > 0 {
+		container.Command = pvcRequestConfig.Command
+	}
+
+	return container
+}
+
+// mcRetryScript reruns the mc command given as its arguments up to
+// PVCI_TRANSFER_RETRIES more times until it succeeds.
+const mcRetryScript = `n=0
+until "$@"; do
+  n=$((n+1))
+  if [ "$n" -gt "$PVCI_TRANSFER_RETRIES" ]; then exit 1; fi
+  echo "retrying copy ($n of $PVCI_TRANSFER_RETRIES)"
+  sleep 5
+done`
+
+// transferRetries returns the retries of a failed copy by the injector
+// of a PVCRequestConfig. Copy plans and custom commands are not
+// retried.
+func (a *API) transferRetries(pvcRequestConfig PVCRequestConfig) int {
+	if usesPlan(pvcRequestConfig) || len(pvcRequestConfig.Command) > 0 {
+		return 0
+	}
+
+	if pvcRequestConfig.TransferRetries > 0 {
+		return pvcRequestConfig.TransferRetries
+	}
+
+	return a.InjectorTransferRetries
+}
+
+// transferTimeout returns the seconds of I/O inactivity after which the
+// copy tool of a PVCRequestConfig retries a request.
+func (a *API) transferTimeout(pvcRequestConfig PVCRequestConfig) int64 {
+	if pvcRequestConfig.TransferTimeout > 0 {
+		return pvcRequestConfig.TransferTimeout
+	}
+
+	return a.InjectorTransferTimeout
+}
+
+// transferRetryContainer maps the transfer retries and timeout of a
+// PVCRequestConfig onto the flags and environment of its copy tool.
+// mc, lacking both, is rerun by a shell loop for its retries.
+func (a *API) transferRetryContainer(container *coreV1.Container, pvcRequestConfig PVCRequestConfig) {
+	retries := a.transferRetries(pvcRequestConfig)
+	timeout := a.transferTimeout(pvcRequestConfig)
+
+	switch pvcRequestConfig.Transport {
+	case TransportRclone:
+		if retries > 0 {
+			container.Command = append(container.Command, "--retries", strconv.Itoa(retries+1))
+		}
+		if timeout > 0 {
+			container.Command = append(container.Command, "--timeout", fmt.Sprintf("%ds", timeout))
+		}
+	case TransportAWSCLI:
+		if retries > 0 {
+			container.Env = append(container.Env,
+				coreV1.EnvVar{Name: "AWS_RETRY_MODE", Value: "standard"},
+				coreV1.EnvVar{Name: "AWS_MAX_ATTEMPTS", Value: strconv.Itoa(retries + 1)},
+			)
+		}
+		if timeout > 0 {
+			container.Command = append(container.Command, "--cli-read-timeout", strconv.FormatInt(timeout, 10))
+		}
+	default:
+		if retries > 0 {
+			container.Command = append([]string{"sh", "-c", mcRetryScript, "sh"}, container.Command...)
+			container.Env = append(container.Env, coreV1.EnvVar{Name: "PVCI_TRANSFER_RETRIES", Value: strconv.Itoa(retries)})
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package pvci
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Tunables are the Config fields updated at runtime through the
+// admin /admin/config endpoint. StallTimeout and MaxInjectorDuration
+// are in seconds, zero disabling them.
+type Tunables struct {
+	AvgMPS               int   `json:"avg_mps"`
+	VolumeOveragePercent int   `json:"volume_overage_percent"`
+	StallTimeout         int64 `json:"stall_timeout"`
+	MaxInjectorDuration  int64 `json:"max_injector_duration"`
+}
+
+// TunablesRequest structures the body of the /admin/config endpoint,
+// omitted fields are left unchanged.
+type TunablesRequest struct {
+	AvgMPS               *int   `json:"avg_mps"`
+	VolumeOveragePercent *int   `json:"volume_overage_percent"`
+	StallTimeout         *int64 `json:"stall_timeout"`
+	MaxInjectorDuration  *int64 `json:"max_injector_duration"`
+}
+
+// validate checks the values of a TunablesRequest.
+func (tr TunablesRequest) validate() error {
+	if tr.AvgMPS != nil && *tr.AvgMPS <= 0 {
+		return badRequest("avg_mps must be greater than 0")
+	}
+	if tr.VolumeOveragePercent != nil && *tr.VolumeOveragePercent < 0 {
+		return badRequest("volume_overage_percent must not be negative")
+	}
+	if tr.StallTimeout != nil && *tr.StallTimeout < 0 {
+		return badRequest("stall_timeout must not be negative")
+	}
+	if tr.MaxInjectorDuration != nil && *tr.MaxInjectorDuration < 0 {
+		return badRequest("max_injector_duration must not be negative")
+	}
+
+	return nil
+}
+
+// tunables returns the current Tunables.
+func (a *API) tunables() Tunables {
+	a.tunablesMu.RLock()
+	defer a.tunablesMu.RUnlock()
+
+	return Tunables{
+		AvgMPS:               a.AvgMPS,
+		VolumeOveragePercent: a.VolumeOveragePercent,
+		StallTimeout:         int64(a.StallTimeout / time.Second),
+		MaxInjectorDuration:  int64(a.MaxInjectorDuration / time.Second),
+	}
+}
+
+// SetTunables validates and applies a TunablesRequest, returning the
+// resulting Tunables. Creates running keep the values they started
+// with where already read.
+func (a *API) SetTunables(tr TunablesRequest) (Tunables, error) {
+	err := tr.validate()
+	if err != nil {
+		return a.tunables(), err
+	}
+
+	a.tunablesMu.Lock()
+	if tr.AvgMPS != nil {
+		a.AvgMPS = *tr.AvgMPS
+	}
+	if tr.VolumeOveragePercent != nil {
+		a.VolumeOveragePercent = *tr.VolumeOveragePercent
+	}
+	if tr.StallTimeout != nil {
+		a.StallTimeout = time.Duration(*tr.StallTimeout) * time.Second
+	}
+	if tr.MaxInjectorDuration != nil {
+		a.MaxInjectorDuration = time.Duration(*tr.MaxInjectorDuration) * time.Second
+	}
+	a.tunablesMu.Unlock()
+
+	return a.tunables(), nil
+}
+
+// TunablesHandler used by the HTTP POST /admin/config endpoint to
+// update runtime tunables, responding with the resulting Tunables.
+func (a *API) TunablesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tunablesRequest := &TunablesRequest{}
+		err := a.parseBody(c, tunablesRequest)
+		if err != nil {
+			a.abortWithParseError(c, err)
+			return
+		}
+
+		tunables, err := a.SetTunables(*tunablesRequest)
+		if err != nil {
+			a.abortWithError(c, err)
+			return
+		}
+
+		a.Log.Warn("tunables changed",
+			zap.Any("tunables", tunables),
+			zap.String("remote_addr", c.ClientIP()),
+		)
+
+		c.JSON(http.StatusOK, tunables)
+	}
+}
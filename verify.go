@@ -0,0 +1,122 @@
+package pvci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultVerifyImage runs the injector's verify container when
+// Config.VerifyImage is not set.
+const DefaultVerifyImage = "busybox"
+
+// verifyContainerName is the injector container counting the files
+// landed on the source PVC once the copy completes.
+const verifyContainerName = "verify"
+
+// Event reasons recorded for verification discrepancies.
+const (
+	EventEmptyVolume         = "EmptyVolume"
+	EventObjectCountMismatch = "ObjectCountMismatch"
+)
+
+// Verification compares the objects sized for a PVC with the files
+// landed on it. LandedObjects is -1 when the count is unknown.
+type Verification struct {
+	ExpectedObjects int64 `json:"expected_objects"`
+	LandedObjects   int64 `json:"landed_objects"`
+	Empty           bool  `json:"empty"`
+	Mismatch        bool  `json:"mismatch"`
+}
+
+// newVerification returns the Verification of expected and landed counts.
+func newVerification(expected int64, landed int64) Verification {
+	return Verification{
+		ExpectedObjects: expected,
+		LandedObjects:   landed,
+		Empty:           landed == 0,
+		Mismatch:        landed >= 0 && landed != expected,
+	}
+}
+
+// countMismatch returns a COUNT_MISMATCH Error when fewer files landed
+// than the objects sized, as when the transfer credentials can not read
+// objects the listing credentials can. An unknown landed count is nil.
+func countMismatch(expected int64, landed int64) error {
+	if landed < 0 || landed >= expected {
+		return nil
+	}
+
+	return newError(ErrCodeCountMismatch, http.StatusBadGateway,
+		"%d files landed from %d objects sized", landed, expected)
+}
+
+// VerificationFromPVC reads the Verification annotated on a PVC
+// created by PVCI.
+func VerificationFromPVC(pvc *coreV1.PersistentVolumeClaim) Verification {
+	expected, _ := strconv.ParseInt(pvc.Annotations["pvci.txn2.com/object_count"], 10, 64)
+
+	landed, err := strconv.ParseInt(pvc.Annotations["pvci.txn2.com/landed_count"], 10, 64)
+	if err != nil {
+		landed = -1
+	}
+
+	return newVerification(expected, landed)
+}
+
+// verifyContainer returns the injector container writing the count of
+// files on the source PVC to its termination message.
+func (a *API) verifyContainer() coreV1.Container {
+	return coreV1.Container{
+		Name:    verifyContainerName,
+		Image:   a.VerifyImage,
+		Command: []string{"sh", "-c", "find /srcpvc -type f | wc -l > /dev/termination-log"},
+		VolumeMounts: []coreV1.VolumeMount{
+			{
+				MountPath: "/srcpvc",
+				Name:      "srcpvc",
+			},
+		},
+	}
+}
+
+// landedObjects returns the file count reported by the verify container
+// of a volume's completed injector pod. Of the pods of a sharded Job,
+// the last to complete reports the largest count, covering every
+// shard.
+func (a *API) landedObjects(namespace string, volName string) (int64, error) {
+	pods, err := a.Cs.CoreV1().Pods(namespace).List(context.Background(), metaV1.ListOptions{
+		LabelSelector: a.injectorSelector(volName),
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	landed := int64(-1)
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != verifyContainerName || cs.State.Terminated == nil || cs.State.Terminated.ExitCode != 0 {
+				continue
+			}
+
+			count, err := strconv.ParseInt(strings.TrimSpace(cs.State.Terminated.Message), 10, 64)
+			if err != nil {
+				return -1, err
+			}
+			if count > landed {
+				landed = count
+			}
+		}
+	}
+
+	if landed < 0 {
+		return -1, fmt.Errorf("no completed %s container found for injector of %s", verifyContainerName, volName)
+	}
+
+	return landed, nil
+}
@@ -0,0 +1,171 @@
+package pvci
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v6"
+	"go.uber.org/zap"
+)
+
+// objectVersion is a Version or DeleteMarker entry of a
+// ListObjectVersions response.
+type objectVersion struct {
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	LastModified time.Time
+	Size         int64
+}
+
+// listVersionsResult is the body of a ListObjectVersions response.
+// see: https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListObjectVersions.html
+type listVersionsResult struct {
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string          `xml:"NextVersionIdMarker"`
+	Versions            []objectVersion `xml:"Version"`
+	DeleteMarkers       []objectVersion `xml:"DeleteMarker"`
+}
+
+// parseAsOf parses the RFC3339 S3AsOf value of an S3Config.
+func parseAsOf(s3Config S3Config) (time.Time, error) {
+	asOf, err := time.Parse(time.RFC3339, s3Config.S3AsOf)
+	if err != nil {
+		return asOf, badRequest("s3_as_of must be an RFC3339 timestamp: %s", err.Error())
+	}
+
+	return asOf, nil
+}
+
+// getSizeAsOf gets the count and size of the object versions current
+// at the S3AsOf time of a PVCRequestConfig. Objects whose current version
+// at that time is a delete marker are excluded. Non-versioned buckets
+// fall back to a plain listing of the latest objects, while failing to
+// read the versioning status, e.g. without s3:GetBucketVersioning, is an
+// error rather than a silent copy of the latest objects.
+func (a *API) getSizeAsOf(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig) (int64, int64, error) {
+	objCount := int64(0)
+	totalSize := int64(0)
+
+	asOf, err := parseAsOf(pvcRequestConfig.S3Config)
+	if err != nil {
+		return objCount, totalSize, err
+	}
+
+	versioning, err := minioClient.GetBucketVersioning(pvcRequestConfig.S3Bucket)
+	if err != nil {
+		return objCount, totalSize, err
+	}
+
+	if versioning.Status == "" {
+		a.Log.Warn("bucket is not versioned, using latest objects",
+			zap.String("bucket", pvcRequestConfig.S3Bucket),
+			zap.String("as_of", pvcRequestConfig.S3AsOf),
+		)
+
+		return a.listSize(minioClient, pvcRequestConfig)
+	}
+
+	// track the newest version or delete marker of each key
+	// at or before the as-of time
+	current := make(map[string]objectVersion)
+	deleted := make(map[string]bool)
+
+	track := func(v objectVersion, isDeleteMarker bool) {
+		if v.LastModified.After(asOf) {
+			return
+		}
+
+		if cv, ok := current[v.Key]; ok && !v.LastModified.After(cv.LastModified) {
+			return
+		}
+
+		current[v.Key] = v
+		deleted[v.Key] = isDeleteMarker
+	}
+
+	keyMarker := ""
+	versionIDMarker := ""
+
+	for {
+		result, err := a.listObjectVersions(minioClient, pvcRequestConfig, keyMarker, versionIDMarker)
+		if err != nil {
+			return objCount, totalSize, err
+		}
+
+		for _, v := range result.Versions {
+			track(v, false)
+		}
+
+		for _, v := range result.DeleteMarkers {
+			track(v, true)
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+
+		keyMarker = result.NextKeyMarker
+		versionIDMarker = result.NextVersionIDMarker
+	}
+
+	for key, v := range current {
+		if deleted[key] {
+			continue
+		}
+		objCount += 1
+		totalSize += v.Size
+	}
+
+	return objCount, totalSize, nil
+}
+
+// listObjectVersions requests a single page of object versions. The
+// MinIO client does not expose version listing, so the request is
+// signed by presigning a GET on the bucket's ?versions sub-resource.
+func (a *API) listObjectVersions(minioClient *minio.Client, pvcRequestConfig PVCRequestConfig, keyMarker string, versionIDMarker string) (*listVersionsResult, error) {
+	params := url.Values{}
+	params.Set("versions", "")
+	params.Set("prefix", pvcRequestConfig.S3Prefix)
+	if keyMarker != "" {
+		params.Set("key-marker", keyMarker)
+		params.Set("version-id-marker", versionIDMarker)
+	}
+
+	u, err := minioClient.Presign(http.MethodGet, pvcRequestConfig.S3Bucket, "", time.Minute, params)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := a.getS3Transport(pvcRequestConfig.S3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to list object versions: %s", resp.Status)
+	}
+
+	result := &listVersionsResult{}
+	err = xml.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
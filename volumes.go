@@ -0,0 +1,54 @@
+package pvci
+
+import (
+	"path"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// reservedVolumes are the injector pod volumes managed by PVCI,
+// by name and mount path.
+var reservedVolumes = map[string]string{
+	"srcpvc":    "/srcpvc",
+	"plan":      "/plan",
+	"mc-config": mcConfigDir,
+}
+
+// checkExtraVolumes validates the extra injector volumes and mounts of
+// a PVCRequestConfig against the volumes reserved by PVCI. Mounts must
+// reference an extra volume.
+func checkExtraVolumes(pvcRequestConfig PVCRequestConfig) error {
+	volumes := make(map[string]bool)
+	for _, volume := range pvcRequestConfig.ExtraVolumes {
+		if _, ok := reservedVolumes[volume.Name]; ok {
+			return badRequest("extra volume name %s is reserved", volume.Name)
+		}
+		if volumes[volume.Name] {
+			return badRequest("extra volume name %s is duplicated", volume.Name)
+		}
+		volumes[volume.Name] = true
+	}
+
+	for _, mount := range pvcRequestConfig.ExtraVolumeMounts {
+		if !volumes[mount.Name] {
+			return badRequest("extra volume mount %s does not reference an extra volume", mount.Name)
+		}
+
+		for _, reserved := range reservedVolumes {
+			if path.Clean(mount.MountPath) == reserved {
+				return badRequest("extra volume mount path %s is reserved", mount.MountPath)
+			}
+		}
+	}
+
+	return nil
+}
+
+// extraVolumesPod adds the extra volumes of a PVCRequestConfig to a
+// pod spec, mounted in its injector container.
+func extraVolumesPod(podSpec *coreV1.PodSpec, pvcRequestConfig PVCRequestConfig) {
+	podSpec.Volumes = append(podSpec.Volumes, pvcRequestConfig.ExtraVolumes...)
+
+	container := &podSpec.InitContainers[0]
+	container.VolumeMounts = append(container.VolumeMounts, pvcRequestConfig.ExtraVolumeMounts...)
+}
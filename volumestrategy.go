@@ -0,0 +1,113 @@
+package pvci
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// Volume strategies of a create. A clone injects a source PVC and
+// clones it to the read-only final PVC. A direct inject writes the
+// final PVC itself, for storage classes unable to clone.
+const (
+	VolumeStrategyClone        = "clone"
+	VolumeStrategyDirectInject = "direct_inject"
+)
+
+// EventDirectInjected is recorded on a final PVC written directly by
+// its injector.
+const EventDirectInjected = "DirectInjected"
+
+// checkVolumeStrategy validates the VolumeStrategy of a VolConfig. A
+// direct inject has no source PVC or clone, so it can not be combined
+// with the options configuring them.
+func checkVolumeStrategy(volConfig VolConfig) error {
+	switch volConfig.VolumeStrategy {
+	case "", VolumeStrategyClone:
+		return nil
+	case VolumeStrategyDirectInject:
+	default:
+		return badRequest("unknown volume_strategy %s, expected %s or %s",
+			volConfig.VolumeStrategy, VolumeStrategyClone, VolumeStrategyDirectInject)
+	}
+
+	if volConfig.FinalStorageClass != "" || volConfig.FinalAccessMode != "" ||
+		volConfig.DebugKeepSource || volConfig.Populator != nil {
+		return badRequest("volume_strategy %s can not be combined with final_storage_class, final_access_mode, debug_keep_source or a populator",
+			VolumeStrategyDirectInject)
+	}
+
+	return nil
+}
+
+// volumeStrategy returns the VolumeStrategy of a validated VolConfig.
+// Creates not setting one clone unless the final storage class is known
+// to be unable to, where they fall back to a direct inject and return
+// a warning. Creates requiring a clone, by their options or
+// Config.RequireCloneSupport, are left to checkCloneSupport.
+func (a *API) volumeStrategy(volConfig VolConfig) (string, string) {
+	if volConfig.VolumeStrategy != "" {
+		return volConfig.VolumeStrategy, ""
+	}
+
+	// options configuring the source PVC or clone require one
+	requiresClone := a.RequireCloneSupport || volConfig.FinalStorageClass != "" || volConfig.FinalAccessMode != "" ||
+		volConfig.DebugKeepSource || volConfig.Populator != nil
+	if requiresClone {
+		return VolumeStrategyClone, ""
+	}
+
+	reason, err := a.cloneUnsupported(volConfig.StorageClass)
+	if err != nil || reason == "" {
+		return VolumeStrategyClone, ""
+	}
+
+	a.Log.Warn("storage class can not clone, falling back to a direct inject",
+		zap.String("name", volConfig.Name),
+		zap.String("namespace", volConfig.Namespace),
+		zap.String("storage_class", volConfig.StorageClass),
+		zap.String("reason", reason),
+	)
+
+	return VolumeStrategyDirectInject, "storage class " + volConfig.StorageClass + " can not clone (" + reason +
+		"), the PVC was injected directly and is writable"
+}
+
+// finishDirectInject annotates the final PVC written by a direct
+// inject as the final clone PVC of a create would be, confirming it
+// mounts for consumers when requested.
+func (a *API) finishDirectInject(pvc *coreV1.PersistentVolumeClaim, pvcRequestConfig PVCRequestConfig, landed int64, warnings []string) error {
+	annotations := map[string]string{}
+	if landed >= 0 {
+		annotations["pvci.txn2.com/landed_count"] = strconv.FormatInt(landed, 10)
+	}
+	if len(warnings) > 0 {
+		annotations[warningsAnnotation] = strings.Join(warnings, "\n")
+	}
+	if pvcRequestConfig.AutoGrow {
+		annotations[autoGrowAnnotation] = "true"
+	}
+	stampRequest(annotations, pvcRequestConfig)
+
+	err := a.annotatePVC(pvc.Namespace, pvc.Name, annotations)
+	if err != nil {
+		return err
+	}
+
+	a.event(pvc, EventDirectInjected, "Injected directly on storage class %s", pvcRequestConfig.StorageClass)
+
+	if pvcRequestConfig.VerifyConsumable {
+		err = a.verifyConsumable(pvcRequestConfig)
+		if err != nil {
+			a.warning(pvc, EventConsumerVerifyFailed, "%s", err.Error())
+			if a.CleanupOnFailure {
+				a.cleanupFinal(pvc.Namespace, pvc.Name, pvc.Name)
+			}
+			return err
+		}
+	}
+
+	return nil
+}